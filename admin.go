@@ -0,0 +1,153 @@
+package mgodo
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+//CollectionStats is result of collStats command, sizes are in bytes
+type CollectionStats struct {
+	Ns             string         `bson:"ns"`
+	Count          int64          `bson:"count"`
+	Size           int64          `bson:"size"`
+	AvgObjSize     int64          `bson:"avgObjSize"`
+	StorageSize    int64          `bson:"storageSize"`
+	NIndexes       int            `bson:"nindexes"`
+	TotalIndexSize int64          `bson:"totalIndexSize"`
+	IndexSizes     map[string]int `bson:"indexSizes"`
+	Capped         bool           `bson:"capped"`
+}
+
+//Stats return statistics of collection
+func (m *Do) Stats() (_ *CollectionStats, err error) {
+	defer m.trace("Stats", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return nil, err
+	}
+	stats := new(CollectionStats)
+	err = m.collection.Database.Run(bson.D{{Name: "collStats", Value: m.collection.Name}}, stats)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+//Truncate remove all documents of collection, include IsRemoved:true, mainly for test teardown
+func (m *Do) Truncate() (err error) {
+	defer m.trace("Truncate", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	_, err = m.collection.RemoveAll(m.tenantQ(bson.M{}))
+	return err
+}
+
+//TruncateWithConfirmation truncate only if collectionName is name of the collection
+func (m *Do) TruncateWithConfirmation(collectionName string) (err error) {
+	defer m.trace("TruncateWithConfirmation", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if collectionName != m.collection.Name {
+		return errors.New("Collection name is not confirmed.")
+	}
+	return m.Truncate()
+}
+
+//DropCollection drop the collection, refuse to drop changelog collection unless force
+//Changelog collection is the one of Do, or a collection named *ChangeLog or *ChangeLogs.
+func (m *Do) DropCollection(force bool) (err error) {
+	defer m.trace("DropCollection", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if !force && m.isLogCollection() {
+		return errors.New("Cannot drop changelog collection without force.")
+	}
+	return m.collection.DropCollection()
+}
+
+//isLogCollection check if collection of Do is its changelog collection or named as one
+func (m *Do) isLogCollection() bool {
+	name := m.collection.Name
+	if m.logCollection != nil && m.logCollection.Name == name {
+		return true
+	}
+	return strings.HasSuffix(name, "ChangeLog") || strings.HasSuffix(name, "ChangeLogs")
+}
+
+//Rename rename the collection to newName in the same database, Do uses newName after
+func (m *Do) Rename(newName string) (err error) {
+	defer m.trace("Rename", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	db := m.collection.Database
+	cmd := bson.D{
+		{Name: "renameCollection", Value: m.collection.FullName},
+		{Name: "to", Value: db.Name + "." + newName},
+	}
+	err = m.session.DB("admin").Run(cmd, nil)
+	if err != nil {
+		return err
+	}
+	m.collection = db.C(newName)
+	return nil
+}
+
+//RunCommand run cmd on database of the collection, e.g. collMod, for commands not wrapped by Do
+func (m *Do) RunCommand(cmd interface{}, result interface{}) (err error) {
+	defer m.trace("RunCommand", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	return m.collection.Database.Run(cmd, result)
+}
+
+//copyBatchSize is count of records inserted per bulk by CopyCollection
+const copyBatchSize = 1000
+
+//CopyCollection insert all records, including IsRemoved:true, to collection destCollectionName of database destDbName
+//Records are copied in bulks of 1000, a failure leaves the copied ones in destination.
+func (m *Do) CopyCollection(destDbName, destCollectionName string) (err error) {
+	defer m.trace("CopyCollection", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	dest := m.session.DB(destDbName).C(destCollectionName)
+	iter := m.find(m.tenantQ(bson.M{})).Iter()
+	docs := make([]interface{}, 0, copyBatchSize)
+	for {
+		var doc bson.M
+		more := iter.Next(&doc)
+		if more {
+			docs = append(docs, doc)
+		}
+		if len(docs) == copyBatchSize || (!more && len(docs) > 0) {
+			bulk := dest.Bulk()
+			bulk.Insert(docs...)
+			if _, err := bulk.Run(); err != nil {
+				iter.Close()
+				return err
+			}
+			docs = docs[:0]
+		}
+		if !more {
+			break
+		}
+	}
+	return iter.Close()
+}
+
+//ListCollections list names of collections in database dbName
+func ListCollections(session *mgo.Session, dbName string) ([]string, error) {
+	return session.DB(dbName).CollectionNames()
+}
+
+//DropDatabase drop database dbName with all its collections
+func DropDatabase(session *mgo.Session, dbName string) error {
+	return session.DB(dbName).DropDatabase()
+}
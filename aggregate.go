@@ -0,0 +1,73 @@
+package mgodo
+
+import (
+	"strings"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+//AggregateQ export mgo.Pipe of pipeline for further chain
+//Stages added automatically:
+//  - $match of m.Query with IsRemoved: true excluded, before pipeline
+//  - $sort, $skip and $limit from m.Sort, m.Skip and m.Limit, after pipeline
+//Everything else ($group, $project, $lookup, ...) is left to the caller.
+func (m *Do) AggregateQ(pipeline []bson.M) *mgo.Pipe {
+	// connect lazy Do, see LazyNewDo
+	m.init()
+	stages := []bson.M{{"$match": m.notRemovedQ()}}
+	stages = append(stages, pipeline...)
+	//sort
+	if m.Sort != nil {
+		stages = append(stages, bson.M{"$sort": sortD(m.Sort)})
+	}
+
+	//skip
+	if m.Skip != 0 {
+		stages = append(stages, bson.M{"$skip": m.Skip})
+	}
+
+	//limit
+	if m.Limit != 0 {
+		stages = append(stages, bson.M{"$limit": m.Limit})
+	}
+	m.refresh()
+	return m.collection.Pipe(stages)
+}
+
+//Aggregate run pipeline and put all results to result, see AggregateQ for added stages
+func (m *Do) Aggregate(pipeline []bson.M, result interface{}) (err error) {
+	defer m.trace("Aggregate", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	err = m.AggregateQ(pipeline).All(result)
+	return err
+}
+
+//sortD convert mgo sort fields ("-field" as descending) to $sort document
+func sortD(fields []string) bson.D {
+	d := bson.D{}
+	for _, f := range fields {
+		order := 1
+		if strings.HasPrefix(f, "-") {
+			order = -1
+			f = f[1:]
+		} else if strings.HasPrefix(f, "+") {
+			f = f[1:]
+		}
+		d = append(d, bson.DocElem{Name: f, Value: order})
+	}
+	return d
+}
+
+//MapReduce run map/reduce job on records of query, skip IsRemoved:true
+//Map/reduce is a legacy API of MongoDB, prefer Aggregate for new code.
+func (m *Do) MapReduce(job *mgo.MapReduce, result interface{}) (err error) {
+	defer m.trace("MapReduce", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	_, err = m.findQ().MapReduce(job, result)
+	return err
+}
@@ -8,13 +8,16 @@ import (
 )
 
 const (
-	UPDATE = "UPDATE"
-	DELETE = "DELETE" // soft delete
-	ERASE  = "ERASE"  // hard delete
-	CREATE = "CREATE" // hard delete
+	UPDATE  = "UPDATE"
+	DELETE  = "DELETE"  // soft delete
+	ERASE   = "ERASE"   // hard delete
+	CREATE  = "CREATE"  // hard delete
+	RESTORE = "RESTORE" // undo soft delete
 )
 
 // BaseModel to be emmbered to other struct as audit trail perpurse
+// Embed it inline (`bson:",inline"`) to have every field Do reads and stamps
+// by reflection: Id, CreatedAt/By, UpdatedAt/By, IsRemoved, RemovedAt/By and IsLocked.
 type BaseModel struct {
 	Id        bson.ObjectId `bson:"_id,omitempty"`
 	CreatedAt time.Time     `bson:"CreatedAt,omitempty"`
@@ -30,9 +33,10 @@ type BaseModel struct {
 //ChangeLog
 type ChangeLog struct {
 	BaseModel    `bson:",inline"`
-	ModelObjId   bson.ObjectId `bson:"ModelObjId,omitempty"`
-	ModelName    string        `bson:"ModelName,omitempty"`
-	ModelValue   interface{}   `bson:"ModelValue,omitempty"`
-	Operation    string        `bson:"Operation,omitempty"`
-	ChangeReason string        `bson:"ChangeReason,omitempty"`
+	ModelObjId   interface{} `bson:"ModelObjId,omitempty"` // Id of record, bson.ObjectId unless CustomId is used
+	ModelName    string      `bson:"ModelName,omitempty"`
+	ModelValue   interface{} `bson:"ModelValue,omitempty"`
+	Operation    string      `bson:"Operation,omitempty"`
+	ChangeReason string      `bson:"ChangeReason,omitempty"`
+	TenantId     string      `bson:"tenant_id,omitempty"` // TenantField of Tenant
 }
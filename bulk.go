@@ -0,0 +1,361 @@
+package mgodo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+//BulkError wrap mgo.BulkError, Failed is the index of docs not written
+type BulkError struct {
+	*mgo.BulkError
+	Failed []int
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("bulk failed for %d document(s) %v: %s", len(e.Failed), e.Failed, e.BulkError.Error())
+}
+
+//newBulkError convert error from mgo.Bulk.Run, other errors are returned as is
+func newBulkError(err error) error {
+	bErr, ok := err.(*mgo.BulkError)
+	if !ok {
+		return err
+	}
+	e := &BulkError{BulkError: bErr}
+	for _, c := range bErr.Cases() {
+		e.Failed = append(e.Failed, c.Index)
+	}
+	return e
+}
+
+//stampCreate generate objectId, set CreatedAt and CreatedBy of doc
+//Id of other types than bson.ObjectId is kept as assigned.
+func (m *Do) stampCreate(doc interface{}) {
+	v := reflect.ValueOf(doc).Elem()
+	if id := v.FieldByName("Id"); id.Type() == reflect.TypeOf(bson.ObjectId("")) {
+		id.Set(reflect.ValueOf(bson.NewObjectId()))
+	}
+	v.FieldByName("CreatedAt").Set(reflect.ValueOf(time.Now()))
+	v.FieldByName("CreatedBy").Set(reflect.ValueOf(m.Operator))
+}
+
+//BulkCreate insert docs in one round-trip, docs are pointers of model
+//Id, CreatedAt and CreatedBy are stamped on every doc before sending.
+//The bulk is unordered, a failed doc does not stop the others. On failure a
+//*BulkError tells which docs are not inserted.
+func (m *Do) BulkCreate(docs []interface{}) (err error) {
+	defer m.trace("BulkCreate", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	for _, doc := range docs {
+		m.stampCreate(doc)
+	}
+	bulk := m.collection.Bulk()
+	bulk.Unordered()
+	bulk.Insert(m.tenantDocs(docs)...)
+	_, err = bulk.Run()
+	return newBulkError(err)
+}
+
+//BulkCreateWithLog bulk create docs and insert one changelog per inserted doc
+//On *BulkError docs not in Failed are logged, then the error is returned.
+func (m *Do) BulkCreateWithLog(docs []interface{}) (err error) {
+	defer m.trace("BulkCreateWithLog", m.enter(), &err)
+	err = m.BulkCreate(docs)
+	var bErr *BulkError
+	if err != nil && !errors.As(err, &bErr) {
+		return err
+	}
+	failed := map[int]bool{}
+	if bErr != nil {
+		for _, i := range bErr.Failed {
+			if i < 0 {
+				// not bound to a doc, which are inserted is unknown
+				return err
+			}
+			failed[i] = true
+		}
+	}
+
+	var logs []interface{}
+	for i, doc := range docs {
+		if !failed[i] {
+			logs = append(logs, m.newLog(doc, CREATE))
+		}
+	}
+	if len(logs) == 0 {
+		return err
+	}
+	bulk := m.logCollection.Bulk()
+	bulk.Insert(logs...)
+	if _, logErr := bulk.Run(); err == nil {
+		err = logErr
+	}
+	return err
+}
+
+//BulkDelete soft delete records of ids in one update, locked records are skipped
+func (m *Do) BulkDelete(ids []bson.ObjectId) (err error) {
+	defer m.trace("BulkDelete", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	return m.softDeleteIds(objectIds(ids))
+}
+
+//BulkDeleteWithLog soft delete records of ids and insert one changelog per deleted record
+func (m *Do) BulkDeleteWithLog(ids []bson.ObjectId) (err error) {
+	defer m.trace("BulkDeleteWithLog", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	return m.softDeleteIdsWithLog(objectIds(ids))
+}
+
+//objectIds convert ids to []interface{}
+func objectIds(ids []bson.ObjectId) []interface{} {
+	result := make([]interface{}, len(ids))
+	for i, id := range ids {
+		result[i] = id
+	}
+	return result
+}
+
+//softDeleteIdsQ is query of records of ids to be soft deleted, locked records are skipped
+func (m *Do) softDeleteIdsQ(ids []interface{}) bson.M {
+	return bson.M{"_id": bson.M{"$in": ids}, "IsLocked": bson.M{"$ne": true}}
+}
+
+//softDeleteIds soft delete records of ids of any type in one update
+func (m *Do) softDeleteIds(ids []interface{}) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	m.uncacheIds(ids)
+	sd := m.softDeleteConfig()
+	update := bson.M{"$set": bson.M{sd.DeletedFlag: true, sd.DeletedAt: time.Now(), sd.DeletedBy: m.Operator}}
+	_, err := m.collection.UpdateAll(m.tenantQ(m.softDeleteIdsQ(ids)), update)
+	return err
+}
+
+//softDeleteIdsWithLog softDeleteIds and insert one changelog per deleted record
+func (m *Do) softDeleteIdsWithLog(ids []interface{}) error {
+	if err := m.softDeleteIds(ids); err != nil || len(ids) == 0 {
+		return err
+	}
+	// log only the deleted records, locked ones are skipped
+	var records []bson.M
+	deleted := bson.M{"$and": []interface{}{m.softDeleteIdsQ(ids), m.removedQ()}}
+	if err := m.collection.Find(m.tenantQ(deleted)).All(&records); err != nil {
+		return err
+	}
+	return m.saveLogs(records, DELETE)
+}
+
+//BulkErase hard delete records of ids in one remove
+func (m *Do) BulkErase(ids []bson.ObjectId) (err error) {
+	defer m.trace("BulkErase", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	m.uncacheIds(objectIds(ids))
+	_, err = m.collection.RemoveAll(m.tenantQ(bson.M{"_id": bson.M{"$in": ids}}))
+	return err
+}
+
+//BulkEraseWithLog hard delete records of ids and insert one changelog per record
+func (m *Do) BulkEraseWithLog(ids []bson.ObjectId) (err error) {
+	defer m.trace("BulkEraseWithLog", m.enter(), &err)
+	// read records before they are gone
+	records, err := m.findRawByIds(ids)
+	if err != nil {
+		return err
+	}
+	err = m.BulkErase(ids)
+	if err != nil {
+		return err
+	}
+	return m.saveLogs(records, ERASE)
+}
+
+//findRawByIds read records of ids, including marked as removed
+func (m *Do) findRawByIds(ids []bson.ObjectId) (_ []bson.M, err error) {
+	var records []bson.M
+	if err := m.ctxErr(); err != nil {
+		return records, err
+	}
+	err = m.collection.Find(m.tenantQ(bson.M{"_id": bson.M{"$in": ids}})).All(&records)
+	return records, err
+}
+
+//saveLogs insert one changelog per record in one round-trip
+func (m *Do) saveLogs(records []bson.M, operation string) (err error) {
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	logs := make([]interface{}, len(records))
+	for i, record := range records {
+		logs[i] = m.newLogOf(record["_id"], record, operation)
+	}
+	bulk := m.logCollection.Bulk()
+	bulk.Insert(logs...)
+	_, err = bulk.Run()
+	return err
+}
+
+//DeleteMany soft delete records of ids in one update, same as BulkDelete
+func (m *Do) DeleteMany(ids []bson.ObjectId) error {
+	return m.BulkDelete(ids)
+}
+
+//DeleteManyWithLog same as BulkDeleteWithLog
+func (m *Do) DeleteManyWithLog(ids []bson.ObjectId) error {
+	return m.BulkDeleteWithLog(ids)
+}
+
+//EraseMany hard delete records of ids in one remove, same as BulkErase
+func (m *Do) EraseMany(ids []bson.ObjectId) error {
+	return m.BulkErase(ids)
+}
+
+//EraseManyWithLog same as BulkEraseWithLog
+func (m *Do) EraseManyWithLog(ids []bson.ObjectId) error {
+	return m.BulkEraseWithLog(ids)
+}
+
+//CreateMany insert models in one unordered bulk, a failed model does not stop the others
+//errs[i] is the error of models[i], nil if inserted. err is returned for
+//failures not bound to a model, e.g. network errors.
+func (m *Do) CreateMany(models []interface{}) (errs []error, err error) {
+	defer m.trace("CreateMany", m.enter(), &err)
+	errs = make([]error, len(models))
+	if err := m.ctxErr(); err != nil {
+		return errs, err
+	}
+	if len(models) == 0 {
+		return errs, nil
+	}
+	for _, model := range models {
+		m.stampCreate(model)
+	}
+	bulk := m.collection.Bulk()
+	bulk.Unordered()
+	bulk.Insert(m.tenantDocs(models)...)
+	_, err = bulk.Run()
+	return itemErrors(errs, err)
+}
+
+//itemErrors put errors of mgo.BulkError cases to errs by index, other error is returned as is
+func itemErrors(errs []error, err error) ([]error, error) {
+	bErr, ok := err.(*mgo.BulkError)
+	if !ok {
+		return errs, err
+	}
+	for _, c := range bErr.Cases() {
+		if c.Index < 0 || c.Index >= len(errs) {
+			// not bound to a model
+			return errs, err
+		}
+		errs[c.Index] = c.Err
+	}
+	return errs, nil
+}
+
+//Seed insert docs as raw data, without stamping Id, CreatedAt or IsRemoved, e.g. test fixtures
+func (m *Do) Seed(docs []interface{}) (err error) {
+	defer m.trace("Seed", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return m.collection.Insert(docs...)
+}
+
+//SaveMany upsert models in one unordered bulk, UpdatedAt and UpdatedBy are stamped on each
+//errs[i] is the error of models[i], nil if saved, locked records are not
+//updated. err is returned for failures not bound to a model.
+func (m *Do) SaveMany(models []interface{}) (errs []error, err error) {
+	defer m.trace("SaveMany", m.enter(), &err)
+	errs = make([]error, len(models))
+	if err := m.ctxErr(); err != nil {
+		return errs, err
+	}
+	if len(models) == 0 {
+		return errs, nil
+	}
+	ids := make([]interface{}, len(models))
+	for i, model := range models {
+		ids[i] = reflect.ValueOf(model).Elem().FieldByName("Id").Interface()
+	}
+	var locked []bson.M
+	err = m.collection.Find(m.tenantQ(bson.M{"_id": bson.M{"$in": ids}, "IsLocked": true})).Select(bson.M{"_id": 1}).All(&locked)
+	if err != nil {
+		return errs, err
+	}
+	isLocked := map[interface{}]bool{}
+	for _, r := range locked {
+		isLocked[r["_id"]] = true
+	}
+
+	bulk := m.collection.Bulk()
+	bulk.Unordered()
+	var index []int // index of model of each bulk op
+	for i, model := range models {
+		if isLocked[ids[i]] {
+			errs[i] = errors.New("Record is locked for update.")
+			continue
+		}
+		v := reflect.ValueOf(model).Elem()
+		v.FieldByName("UpdatedAt").Set(reflect.ValueOf(time.Now()))
+		v.FieldByName("UpdatedBy").Set(reflect.ValueOf(m.Operator))
+		bulk.Upsert(m.tenantQ(bson.M{"_id": ids[i]}), bson.M{"$set": m.tenantDoc(model)})
+		index = append(index, i)
+	}
+	if len(index) == 0 {
+		return errs, nil
+	}
+	_, err = bulk.Run()
+	opErrs, err := itemErrors(make([]error, len(index)), err)
+	for op, e := range opErrs {
+		if e != nil {
+			errs[index[op]] = e
+		}
+	}
+	return errs, err
+}
+
+//SaveManyWithLog SaveMany and insert one changelog per saved model
+func (m *Do) SaveManyWithLog(models []interface{}) (errs []error, err error) {
+	defer m.trace("SaveManyWithLog", m.enter(), &err)
+	errs, err = m.SaveMany(models)
+	if err != nil {
+		return errs, err
+	}
+	var logs []interface{}
+	for i, model := range models {
+		if errs[i] == nil {
+			logs = append(logs, m.newLog(model, UPDATE))
+		}
+	}
+	if len(logs) == 0 {
+		return errs, nil
+	}
+	return errs, m.logCollection.Insert(logs...)
+}
@@ -0,0 +1,59 @@
+// Package cache provide cache of records for mgodo.Do.WithCache
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+//Provider store values by key, each for ttl
+type Provider interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{}, ttl time.Duration)
+	Delete(key string)
+}
+
+//entry is value of Memory with its expire time, zero for never
+type entry struct {
+	value   interface{}
+	expires time.Time
+}
+
+//Memory is Provider in memory of the process, safe for concurrent use
+//Expired values are removed when they are read.
+type Memory struct {
+	entries sync.Map
+}
+
+//NewMemory create an empty Memory
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+//Get return value of key, false if not found or expired
+func (c *Memory) Get(key string) (interface{}, bool) {
+	v, ok := c.entries.Load(key)
+	if !ok {
+		return nil, false
+	}
+	e := v.(entry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.entries.Delete(key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+//Set store value of key for ttl, ttl <= 0 to never expire
+func (c *Memory) Set(key string, value interface{}, ttl time.Duration) {
+	e := entry{value: value}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	c.entries.Store(key, e)
+}
+
+//Delete remove value of key
+func (c *Memory) Delete(key string) {
+	c.entries.Delete(key)
+}
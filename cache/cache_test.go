@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryExpire(t *testing.T) {
+	c := NewMemory()
+	c.Set("short", 1, 20*time.Millisecond)
+	c.Set("forever", 2, 0)
+	if v, ok := c.Get("short"); !ok || v != 1 {
+		t.Errorf("Value should be cached before expired, got %v %v", v, ok)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if v, ok := c.Get("short"); ok {
+		t.Errorf("Expired value should not be returned, got %v", v)
+	}
+	if _, ok := c.entries.Load("short"); ok {
+		t.Errorf("Expired value should be removed when read")
+	}
+	if v, ok := c.Get("forever"); !ok || v != 2 {
+		t.Errorf("Value of ttl 0 should never expire, got %v %v", v, ok)
+	}
+
+	c.Delete("forever")
+	if _, ok := c.Get("forever"); ok {
+		t.Errorf("Deleted value should not be returned")
+	}
+}
@@ -0,0 +1,85 @@
+package mgodo
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+
+	"mgodo/cache"
+)
+
+//WithCache let Get read record of model _id from p before MongoDB, cached for ttl
+//Create, Save, Delete, Erase, Restore, DirectSave and updates of model _id
+//remove it from p, BulkDelete and BulkErase remove their ids. Writes by query
+//do not, keep ttl short if they are used.
+func (m *Do) WithCache(p cache.Provider, ttl time.Duration) *Do {
+	m.cache = p
+	m.cacheTTL = ttl
+	return m
+}
+
+//cacheKey is key of model _id in cache
+func (m *Do) cacheKey() string {
+	return m.cacheKeyOf(modelId(m.model))
+}
+
+//cacheKeyOf is key of record id in cache
+func (m *Do) cacheKeyOf(id interface{}) string {
+	return fmt.Sprintf("%s/%s/%v", m.collection.FullName, m.tenantId, id)
+}
+
+//fromCache copy cached record of model _id to model, false if not cached
+//The record is cached as bson, so models filled from it share no maps or slices.
+func (m *Do) fromCache() bool {
+	// a projection is not the whole record, RefreshBeforeRead asks for the latest
+	if m.cache == nil || m.selected != nil || m.RefreshBeforeRead {
+		return false
+	}
+	v, ok := m.cache.Get(m.cacheKey())
+	if !ok {
+		return false
+	}
+	data, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+	model := reflect.ValueOf(m.model).Elem()
+	cached := reflect.New(model.Type())
+	if err := bson.Unmarshal(data, cached.Interface()); err != nil {
+		return false
+	}
+	model.Set(cached.Elem())
+	return true
+}
+
+//toCache cache a deep copy of model as bson
+func (m *Do) toCache() {
+	if m.cache == nil || m.selected != nil {
+		return
+	}
+	data, err := bson.Marshal(m.model)
+	if err != nil {
+		return
+	}
+	m.cache.Set(m.cacheKey(), data, m.cacheTTL)
+}
+
+//uncache remove model _id from cache
+func (m *Do) uncache() {
+	if m.cache == nil {
+		return
+	}
+	m.cache.Delete(m.cacheKey())
+}
+
+//uncacheIds remove records of ids from cache
+func (m *Do) uncacheIds(ids []interface{}) {
+	if m.cache == nil {
+		return
+	}
+	for _, id := range ids {
+		m.cache.Delete(m.cacheKeyOf(id))
+	}
+}
@@ -0,0 +1,95 @@
+package mgodo
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+//findLogQ conduct mgo.Query on ChangeLog collection, skip IsRemoved: true
+//Sort, Skip and Limit of Do are applied.
+func (m *Do) findLogQ(q bson.M) *mgo.Query {
+	q["$and"] = []interface{}{bson.M{"is_removed": bson.M{"$ne": true}}, bson.M{"IsRemoved": bson.M{"$ne": true}}}
+	query := m.logCollection.Find(m.tenantQ(q))
+	//sort
+	if m.Sort != nil {
+		query = query.Sort(m.Sort...)
+	}
+
+	//skip
+	if m.Skip != 0 {
+		query = query.Skip(m.Skip)
+	}
+
+	//limit
+	if m.Limit != 0 {
+		query = query.Limit(m.Limit)
+	}
+	return query
+}
+
+//GetChangeLogs get change logs of one record
+func (m *Do) GetChangeLogs(modelName string, objId interface{}) (_ []ChangeLog, err error) {
+	defer m.trace("GetChangeLogs", m.enter(), &err)
+	var logs []ChangeLog
+	if err := m.ctxErr(); err != nil {
+		return logs, err
+	}
+	err = m.findLogQ(bson.M{"ModelName": modelName, "ModelObjId": objId}).All(&logs)
+	return logs, err
+}
+
+//GetChangeLogsByOperator get change logs made by operator since given time
+func (m *Do) GetChangeLogsByOperator(operator string, since time.Time) (_ []ChangeLog, err error) {
+	defer m.trace("GetChangeLogsByOperator", m.enter(), &err)
+	var logs []ChangeLog
+	if err := m.ctxErr(); err != nil {
+		return logs, err
+	}
+	err = m.findLogQ(bson.M{"CreatedBy": operator, "CreatedAt": bson.M{"$gte": since}}).All(&logs)
+	return logs, err
+}
+
+//GetChangeLogDiff compare ModelValue of two change logs, id1 as old and id2 as new
+//Changed fields are returned as field name to [old value, new value].
+func (m *Do) GetChangeLogDiff(id1, id2 bson.ObjectId) (_ map[string]interface{}, err error) {
+	defer m.trace("GetChangeLogDiff", m.enter(), &err)
+	diff := map[string]interface{}{}
+	if err := m.ctxErr(); err != nil {
+		return diff, err
+	}
+	var oldLog, newLog struct {
+		ModelValue bson.M `bson:"ModelValue"`
+	}
+	if err := m.logCollection.Find(m.tenantQ(bson.M{"_id": id1})).One(&oldLog); err != nil {
+		return diff, err
+	}
+	if err := m.logCollection.Find(m.tenantQ(bson.M{"_id": id2})).One(&newLog); err != nil {
+		return diff, err
+	}
+
+	for k, v := range oldLog.ModelValue {
+		if nv, found := newLog.ModelValue[k]; !found || !reflect.DeepEqual(v, nv) {
+			diff[k] = []interface{}{v, nv}
+		}
+	}
+	for k, nv := range newLog.ModelValue {
+		if _, found := oldLog.ModelValue[k]; !found {
+			diff[k] = []interface{}{nil, nv}
+		}
+	}
+	return diff, nil
+}
+
+//Audit return the timeline of change logs of record id, oldest first
+func (m *Do) Audit(id interface{}) (_ []ChangeLog, err error) {
+	defer m.trace("Audit", m.enter(), &err)
+	var logs []ChangeLog
+	if err := m.ctxErr(); err != nil {
+		return logs, err
+	}
+	err = m.logCollection.Find(m.tenantQ(bson.M{"ModelObjId": id})).Sort("CreatedAt").All(&logs)
+	return logs, err
+}
@@ -0,0 +1,42 @@
+package mgodo
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+//Checkpoint keep a copy of current state of model for Rollback
+func (m *Do) Checkpoint() error {
+	data, err := bson.Marshal(m.model)
+	if err != nil {
+		return err
+	}
+	m.checkpoint = data
+	return nil
+}
+
+//Rollback restore model to state of last Checkpoint and write it back by ReplaceOne
+//The whole record is replaced, so fields zero at Checkpoint, e.g. IsRemoved
+//of a Delete since, are restored as well. Fields not stored, unexported or
+//bson:"-", keep their current value.
+func (m *Do) Rollback() (err error) {
+	defer m.trace("Rollback", m.enter(), &err)
+	if m.checkpoint == nil {
+		return errors.New("No checkpoint to rollback.")
+	}
+	model := reflect.ValueOf(m.model).Elem()
+	restored := reflect.New(model.Type()).Elem()
+	if err := bson.Unmarshal(m.checkpoint, restored.Addr().Interface()); err != nil {
+		return err
+	}
+	for i := 0; i < model.NumField(); i++ {
+		f := model.Type().Field(i)
+		if f.PkgPath != "" || f.Tag.Get("bson") == "-" {
+			continue
+		}
+		model.Field(i).Set(restored.Field(i))
+	}
+	return m.ReplaceOne()
+}
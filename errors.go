@@ -0,0 +1,57 @@
+package mgodo
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+//DoError wrap error returned by operations of Do with what failed
+//Use errors.Is(err, mgo.ErrNotFound) instead of err == mgo.ErrNotFound, and
+//errors.As to get the DoError, e.g. mgo.IsDup(doErr.Err).
+type DoError struct {
+	Err        error
+	ModelName  string
+	Operation  string
+	DocumentId interface{} // Id of model, nil if model has no Id
+	OccurredAt time.Time
+}
+
+func (e *DoError) Error() string {
+	return fmt.Sprintf("%s of %s %v: %s", e.Operation, e.ModelName, e.DocumentId, e.Err.Error())
+}
+
+//Unwrap return the original error
+func (e *DoError) Unwrap() error {
+	return e.Err
+}
+
+//wrapError wrap err of operation in DoError, err already wrapped is returned as is
+func (m *Do) wrapError(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*DoError); ok {
+		return err
+	}
+	return &DoError{
+		Err:        err,
+		ModelName:  getModelName(m.model),
+		Operation:  operation,
+		DocumentId: modelId(m.model),
+		OccurredAt: time.Now(),
+	}
+}
+
+//modelId reflect Id of model, nil if there is none
+func modelId(model interface{}) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(model))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	id := v.FieldByName("Id")
+	if !id.IsValid() {
+		return nil
+	}
+	return id.Interface()
+}
@@ -0,0 +1,45 @@
+package mgodo
+
+import (
+	"sync"
+)
+
+//EventHandler is called with model name and record id of the event
+//id is the Id of model as is, bson.ObjectId, or string, int, ... of CustomId.
+type EventHandler func(modelName string, id interface{})
+
+//EventBus dispatch data change events to subscribed handlers
+//Do publishes CREATE after Create, UPDATE after SaveWithLog, DELETE after
+//DeleteWithLog and ERASE after EraseWithLog, only when they succeed.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+}
+
+//Events is the global EventBus used by Do
+var Events = &EventBus{}
+
+//Subscribe add handler of event, handlers are called synchronously in subscribe order
+func (b *EventBus) Subscribe(event string, handler func(modelName string, id interface{})) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handlers == nil {
+		b.handlers = map[string][]EventHandler{}
+	}
+	b.handlers[event] = append(b.handlers[event], handler)
+}
+
+//Publish call all handlers of event
+func (b *EventBus) Publish(event, modelName string, id interface{}) {
+	b.mu.RLock()
+	handlers := b.handlers[event]
+	b.mu.RUnlock()
+	for _, h := range handlers {
+		h(modelName, id)
+	}
+}
+
+//publish event of model
+func (m *Do) publish(event string) {
+	Events.Publish(event, getModelName(m.model), modelId(m.model))
+}
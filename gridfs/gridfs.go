@@ -0,0 +1,65 @@
+// Package gridfs store large files in GridFS, linked to a model record by its _id
+package gridfs
+
+import (
+	"io"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+//Metadata is saved with each file to link it to the model record
+type Metadata struct {
+	ModelId bson.ObjectId `bson:"ModelId"`
+}
+
+//GridStore wrap mgo GridFS of Prefix, "fs" if empty
+type GridStore struct {
+	Prefix string
+}
+
+//gridFS return mgo.GridFS of dbName
+func (g *GridStore) gridFS(session *mgo.Session, dbName string) *mgo.GridFS {
+	prefix := g.Prefix
+	if prefix == "" {
+		prefix = "fs"
+	}
+	return session.DB(dbName).GridFS(prefix)
+}
+
+//StoreFile save content of r as filename, tagged with modelId
+func (g *GridStore) StoreFile(session *mgo.Session, dbName string, modelId bson.ObjectId, filename string, r io.Reader) (*mgo.GridFile, error) {
+	file, err := g.gridFS(session, dbName).Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	file.SetMeta(Metadata{ModelId: modelId})
+	if _, err = io.Copy(file, r); err != nil {
+		file.Abort()
+		file.Close()
+		return nil, err
+	}
+	if err = file.Close(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+//OpenFile open the latest file tagged with modelId, caller should close it
+//mgo.ErrNotFound is returned if no file is stored for modelId.
+func (g *GridStore) OpenFile(session *mgo.Session, dbName string, modelId bson.ObjectId) (io.ReadCloser, error) {
+	gfs := g.gridFS(session, dbName)
+	iter := gfs.Find(bson.M{"metadata.ModelId": modelId}).Sort("-uploadDate").Limit(1).Iter()
+	var file *mgo.GridFile
+	found := gfs.OpenNext(iter, &file)
+	if err := iter.Close(); err != nil {
+		if found {
+			file.Close()
+		}
+		return nil, err
+	}
+	if !found {
+		return nil, mgo.ErrNotFound
+	}
+	return file, nil
+}
@@ -0,0 +1,37 @@
+package mgodo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/globalsign/mgo"
+)
+
+//HealthCheck ping MongoDB server of session
+func HealthCheck(s *mgo.Session) error {
+	if s == nil {
+		return fmt.Errorf("MongoDB health check failed: no session")
+	}
+	if err := s.Ping(); err != nil {
+		servers := strings.Join(s.LiveServers(), ",")
+		if servers == "" {
+			servers = "no live server"
+		}
+		return fmt.Errorf("MongoDB health check failed (%s): %s", servers, err)
+	}
+	return nil
+}
+
+//HTTPHandler respond 200 OK if HealthCheck passes, otherwise 503 Service Unavailable
+//Use it as liveness or readiness probe.
+func HTTPHandler(s *mgo.Session) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := HealthCheck(s); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+}
@@ -0,0 +1,88 @@
+package mgodo
+
+//Validatable to be implemented by model validating itself before write
+//Validate is called by Create, Save and Delete before any field is stamped,
+//so the model sees its own state. Non-nil error aborts the operation.
+type Validatable interface {
+	Validate() error
+}
+
+//validate call Validate of model if implemented
+func (m *Do) validate() error {
+	if v, ok := m.model.(Validatable); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+//BeforeSave to be implemented by model, called before Create and Save
+//Non-nil error aborts the operation.
+type BeforeSave interface {
+	BeforeSave() error
+}
+
+//AfterSave to be implemented by model, always called after Create and Save with its error
+type AfterSave interface {
+	AfterSave(err error)
+}
+
+//BeforeDelete to be implemented by model, called before Delete (soft delete)
+//Non-nil error aborts the operation.
+type BeforeDelete interface {
+	BeforeDelete() error
+}
+
+//AfterDelete to be implemented by model, always called after Delete with its error
+type AfterDelete interface {
+	AfterDelete(err error)
+}
+
+//BeforeErase to be implemented by model, called before Erase (hard delete)
+//Non-nil error aborts the operation.
+type BeforeErase interface {
+	BeforeErase() error
+}
+
+//AfterErase to be implemented by model, always called after Erase with its error
+type AfterErase interface {
+	AfterErase(err error)
+}
+
+func (m *Do) beforeSave() error {
+	if h, ok := m.model.(BeforeSave); ok {
+		return h.BeforeSave()
+	}
+	return nil
+}
+
+func (m *Do) afterSave(err error) {
+	if h, ok := m.model.(AfterSave); ok {
+		h.AfterSave(err)
+	}
+}
+
+func (m *Do) beforeDelete() error {
+	if h, ok := m.model.(BeforeDelete); ok {
+		return h.BeforeDelete()
+	}
+	return nil
+}
+
+func (m *Do) afterDelete(err error) {
+	if h, ok := m.model.(AfterDelete); ok {
+		h.AfterDelete(err)
+	}
+}
+
+func (m *Do) beforeErase() error {
+	if h, ok := m.model.(BeforeErase); ok {
+		return h.BeforeErase()
+	}
+	return nil
+}
+
+func (m *Do) afterErase(err error) {
+	if h, ok := m.model.(AfterErase); ok {
+		h.AfterErase(err)
+	}
+}
@@ -0,0 +1,85 @@
+package mgodo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo"
+)
+
+//EnsureIndex create index if not exists
+func (m *Do) EnsureIndex(index mgo.Index) (err error) {
+	defer m.trace("EnsureIndex", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	return m.collection.EnsureIndex(index)
+}
+
+//DropIndex drop index of key, "-field" for descending
+func (m *Do) DropIndex(key []string) (err error) {
+	defer m.trace("DropIndex", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	return m.collection.DropIndex(key...)
+}
+
+//ListIndexes list all indexes of collection
+func (m *Do) ListIndexes() (_ []mgo.Index, err error) {
+	defer m.trace("ListIndexes", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return nil, err
+	}
+	return m.collection.Indexes()
+}
+
+//UniqueIndex create unique sparse index of fields
+func (m *Do) UniqueIndex(fields ...string) error {
+	return m.EnsureIndex(mgo.Index{Key: fields, Unique: true, Sparse: true})
+}
+
+//CreateTTLIndex expire records expireAfter the time of field, e.g. CreatedAt
+//MongoDB removes expired records in background about every 60 seconds.
+func (m *Do) CreateTTLIndex(field string, expireAfter time.Duration) error {
+	return m.EnsureIndex(mgo.Index{Key: []string{field}, ExpireAfter: expireAfter})
+}
+
+//IndexesError tell which indexes of EnsureIndexes are created and which failed
+type IndexesError struct {
+	Succeeded []mgo.Index
+	Failed    []mgo.Index
+	Errs      []error // Errs[i] is the error of Failed[i]
+}
+
+func (e *IndexesError) Error() string {
+	msgs := make([]string, len(e.Failed))
+	for i, index := range e.Failed {
+		msgs[i] = fmt.Sprintf("%v: %s", index.Key, e.Errs[i])
+	}
+	return fmt.Sprintf("ensure failed for %d of %d index(es): %s", len(e.Failed), len(e.Failed)+len(e.Succeeded), strings.Join(msgs, "; "))
+}
+
+//EnsureIndexes create all indexes not existing, a failed index does not stop the others
+//If any fails, an *IndexesError is returned. MongoDB has no atomic creation of
+//several indexes, the succeeded ones are kept.
+func (m *Do) EnsureIndexes(indexes []mgo.Index) (err error) {
+	defer m.trace("EnsureIndexes", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	e := new(IndexesError)
+	for _, index := range indexes {
+		if err := m.collection.EnsureIndex(index); err != nil {
+			e.Failed = append(e.Failed, index)
+			e.Errs = append(e.Errs, err)
+		} else {
+			e.Succeeded = append(e.Succeeded, index)
+		}
+	}
+	if len(e.Failed) > 0 {
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mgodo
+
+import (
+	"github.com/globalsign/mgo"
+)
+
+//lazyInit is what LazyNewDo needs to initialize Do on first use
+type lazyInit struct {
+	connect       func() (*mgo.Session, error)
+	dbName        string
+	logCollection string
+	options       []func(s *mgo.Session) // of WithTimeout, ... applied on connect
+}
+
+//LazyNewDo create a Do which connects on first use instead of now, e.g. in DI containers
+//connect is called by the first operation until it succeeds, operations
+//return its error. Its session is copied, options of session like WithTimeout
+//are applied to the copy. Close to release the copy. Q, Iter, AggregateQ and
+//FindIterWithBatchSize can not return the error, they panic if connect fails.
+func LazyNewDo(connect func() (*mgo.Session, error), dbName string, model interface{}) *Do {
+	return &Do{model: model, lazy: &lazyInit{connect: connect, dbName: dbName, logCollection: "ChangeLog"}}
+}
+
+//init connect lazy Do, nothing to do for others
+func (m *Do) init() error {
+	if m.lazy == nil {
+		return nil
+	}
+	s, err := m.lazy.connect()
+	if err != nil {
+		return err
+	}
+	options := m.lazy.options
+	m.bind(s.Copy())
+	m.ownSession = true
+	for _, option := range options {
+		option(m.session)
+	}
+	return nil
+}
+
+//bind session to lazy Do
+func (m *Do) bind(s *mgo.Session) {
+	m.session = s
+	m.collection = Collection(s, m.lazy.dbName, m.model)
+	m.logCollection = s.DB(m.lazy.dbName).C(m.lazy.logCollection)
+	m.lazy = nil
+}
@@ -0,0 +1,71 @@
+package mgodo
+
+import (
+	"log"
+	"time"
+)
+
+//Logger is called after each DB operation of Do with its duration and error
+type Logger interface {
+	Log(operation, collection string, duration time.Duration, err error)
+}
+
+//NoopLogger log nothing, the default Logger
+type NoopLogger struct{}
+
+//Log do nothing
+func (NoopLogger) Log(operation, collection string, duration time.Duration, err error) {}
+
+//SimpleLogger write operations to standard log
+type SimpleLogger struct{}
+
+//Log write operation with log.Printf
+func (SimpleLogger) Log(operation, collection string, duration time.Duration, err error) {
+	if err != nil {
+		log.Printf("mgodo %s %s took %v, error: %s", operation, collection, duration, err)
+		return
+	}
+	log.Printf("mgodo %s %s took %v", operation, collection, duration)
+}
+
+var logger Logger = NoopLogger{}
+
+//SetLogger set Logger of all Do, nil to log nothing
+//It is not safe to call while operations are running.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = NoopLogger{}
+	}
+	logger = l
+}
+
+//enter start an operation, return its start time for trace
+func (m *Do) enter() time.Time {
+	m.depth++
+	return time.Now()
+}
+
+//trace log and measure operation started at start by enter
+//err points to the result of operation, nil if it has no error. Operations
+//called by another one are not traced, the error is wrapped by the outermost.
+func (m *Do) trace(operation string, start time.Time, err *error) {
+	m.depth--
+	if m.depth > 0 {
+		return
+	}
+	var e error
+	if err != nil {
+		e = *err
+	}
+	d := time.Since(start)
+	name := ""
+	if m.collection != nil {
+		name = m.collection.Name
+	}
+	logger.Log(operation, name, d, e)
+	metrics.ObserveDuration(operation, name, d)
+	if e != nil {
+		metrics.IncError(operation, name)
+		*err = m.wrapError(operation, e)
+	}
+}
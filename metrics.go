@@ -0,0 +1,29 @@
+package mgodo
+
+import (
+	"time"
+)
+
+//Metrics record duration and errors of DB operations of Do
+//PrometheusMetrics is built with the prometheus build tag, go build -tags prometheus.
+type Metrics interface {
+	ObserveDuration(operation, collection string, d time.Duration)
+	IncError(operation, collection string)
+}
+
+//noopMetrics record nothing, the default Metrics
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveDuration(operation, collection string, d time.Duration) {}
+func (noopMetrics) IncError(operation, collection string)                         {}
+
+var metrics Metrics = noopMetrics{}
+
+//SetMetrics set Metrics of all Do, nil to record nothing
+//It is not safe to call while operations are running.
+func SetMetrics(m Metrics) {
+	if m == nil {
+		m = noopMetrics{}
+	}
+	metrics = m
+}
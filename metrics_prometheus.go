@@ -0,0 +1,45 @@
+//go:build prometheus
+// +build prometheus
+
+package mgodo
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ Metrics = (*PrometheusMetrics)(nil)
+
+//PrometheusMetrics record Metrics as prometheus histogram and counter
+//labeled by operation and collection.
+type PrometheusMetrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+//NewPrometheusMetrics create PrometheusMetrics and register it to reg
+func NewPrometheusMetrics(reg prometheus.Registerer) *PrometheusMetrics {
+	p := &PrometheusMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "mgodo_operation_duration_seconds",
+			Help: "Duration of mgodo DB operations.",
+		}, []string{"operation", "collection"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mgodo_operation_errors_total",
+			Help: "Number of mgodo DB operations returning error.",
+		}, []string{"operation", "collection"}),
+	}
+	reg.MustRegister(p.duration, p.errors)
+	return p
+}
+
+//ObserveDuration observe d in seconds
+func (p *PrometheusMetrics) ObserveDuration(operation, collection string, d time.Duration) {
+	p.duration.WithLabelValues(operation, collection).Observe(d.Seconds())
+}
+
+//IncError increase error counter
+func (p *PrometheusMetrics) IncError(operation, collection string) {
+	p.errors.WithLabelValues(operation, collection).Inc()
+}
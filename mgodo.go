@@ -1,6 +1,7 @@
 package mgodo
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"strings"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
+
+	"mgodo/cache"
 )
 
 //Do wrap all common functions
@@ -22,8 +25,47 @@ type Do struct {
 	Limit         int
 	Operator      string
 	Reason        string
+	// refresh session before each read to read latest writes, e.g. after failover
+	// The session is copied first, Close to release the copy. Get skips cache.
+	RefreshBeforeRead bool
+
+	ctx        context.Context
+	ownSession bool          // session is a private copy, closed by Close
+	watchStop  chan struct{} // stop change stream of Watch
+	pool       *Pool         // pooled is released to pool by Close
+	pooled     *mgo.Session  // session acquired from pool, never modified
+	attempts   int           // max attempts of retry on transient error
+	backoff    time.Duration
+	selected   bson.M // projection of Select
+	softDelete *SoftDeleteConfig
+	customId   interface{} // Id of next Create instead of a new ObjectId
+	tenantId   string      // scope of Tenant
+	lazy       *lazyInit   // not connected yet, see LazyNewDo
+	checkpoint []byte      // bson of model saved by Checkpoint
+	cache      cache.Provider
+	cacheTTL   time.Duration
+	depth      int // of running operations, only the outermost is traced
+}
+
+//Doer is the common operations of Do
+//Depend on Doer instead of *Do to replace it with mgodotest.MockSession in tests.
+type Doer interface {
+	Create() error
+	CreateWithLog() error
+	Save() error
+	SaveWithLog() error
+	Delete() error
+	DeleteWithLog() error
+	Erase() error
+	EraseWithLog() error
+	Get() error
+	GetByQ() error
+	FindAll(i interface{}) error
+	Count() int64
 }
 
+var _ Doer = (*Do)(nil)
+
 //NewDo initiate with input model and mgo session
 func NewDo(s *mgo.Session, dbName string, model interface{}) *Do {
 	do := &Do{model: model, session: s}
@@ -54,10 +96,340 @@ func NewWithC(s *mgo.Session, model interface{}, cName string) *Do {
 	return do
 }
 
+//NewDoWithCollectionName use collectionName instead of the name reflected from model
+func NewDoWithCollectionName(s *mgo.Session, dbName, collectionName string, model interface{}, operator, reason string) *Do {
+	do := &Do{model: model, session: s}
+	do.collection = s.DB(dbName).C(collectionName)
+	do.logCollection = Collection(s, dbName, "ChangeLog")
+	do.Operator = operator
+	do.Reason = reason
+	return do
+}
+
+//WithLogCollection write and read change logs of Do in collection name instead of "ChangeLog"
+//e.g. "OrderChangeLogs" for Order model, to reduce contention of a shared collection.
+func (m *Do) WithLogCollection(name string) *Do {
+	if m.lazy != nil {
+		m.lazy.logCollection = name
+		return m
+	}
+	m.logCollection = m.collection.Database.C(name)
+	return m
+}
+
+//WithOperatorAndReason set Operator and Reason of following changes and logs
+func (m *Do) WithOperatorAndReason(operator, reason string) *Do {
+	m.Operator = operator
+	m.Reason = reason
+	return m
+}
+
+//WithContext bind ctx to all following operations of Do.
+//A cancelled or expired ctx makes operations return ctx.Err() before touching
+//MongoDB. If ctx has a deadline, the session is copied and its socket timeout
+//set to the remaining time, so a slow server cannot block past the deadline.
+//mgo has no way to abort a running request, cancellation without deadline is
+//only checked between operations. Call Close to release the copied session.
+func (m *Do) WithContext(ctx context.Context) *Do {
+	m.ctx = ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		m.setSession(func(s *mgo.Session) {
+			if d := time.Until(deadline); d > 0 {
+				s.SetSocketTimeout(d)
+			}
+		})
+	}
+	return m
+}
+
+//WithTimeout fail operations not answered within d, on a copy of session
+//Call Close to return the copied session to pool.
+func (m *Do) WithTimeout(d time.Duration) *Do {
+	m.setSession(func(s *mgo.Session) {
+		s.SetSocketTimeout(d)
+	})
+	return m
+}
+
+//WithReadPreference read with mode (mgo.Secondary, mgo.Nearest, ...) on a copy of session
+//Call Close to return the copied session to pool.
+func (m *Do) WithReadPreference(mode mgo.Mode) *Do {
+	m.setSession(func(s *mgo.Session) {
+		s.SetMode(mode, true)
+	})
+	return m
+}
+
+//WithWriteConcern write with w, j and wtimeout on a copy of session
+//w=0 makes writes fire-and-forget, errors of the server are not reported.
+//Call Close to return the copied session to pool.
+func (m *Do) WithWriteConcern(w int, j bool, wtimeout time.Duration) *Do {
+	var safe *mgo.Safe
+	if w != 0 || j {
+		safe = &mgo.Safe{W: w, J: j, WTimeout: int(wtimeout / time.Millisecond)}
+	}
+	m.setSession(func(s *mgo.Session) {
+		s.SetSafe(safe)
+	})
+	return m
+}
+
+//WithSession run following operations on s as is, without copy
+//NewDo does not copy its session either, but With* options above do. The
+//caller owns s and closes it, a session copied before by Do is released.
+func (m *Do) WithSession(s *mgo.Session) *Do {
+	if m.lazy != nil {
+		m.bind(s)
+		return m
+	}
+	m.releaseSession()
+	m.session = s
+	m.collection = m.collection.With(s)
+	m.logCollection = m.logCollection.With(s)
+	return m
+}
+
+//Close stop change stream of Watch and release session copied by Do
+//The session passed to NewDo is not closed.
+func (m *Do) Close() {
+	m.stopWatch()
+	m.releaseSession()
+}
+
+//releaseSession close session copied by Do and return session acquired from pool
+func (m *Do) releaseSession() {
+	if m.ownSession {
+		m.session.Close()
+		m.ownSession = false
+	}
+	if m.pool != nil {
+		m.pool.Release(m.pooled)
+		m.pool = nil
+		m.pooled = nil
+	}
+}
+
+//copySession switch Do to a private copy of its session
+//A lazy Do gets its copy when it connects.
+func (m *Do) copySession() {
+	if m.lazy != nil || m.ownSession {
+		return
+	}
+	m.session = m.session.Copy()
+	m.collection = m.collection.With(m.session)
+	m.logCollection = m.logCollection.With(m.session)
+	m.ownSession = true
+}
+
+//setSession apply option to a private copy of session
+//Options of a lazy Do are kept until it connects.
+func (m *Do) setSession(option func(s *mgo.Session)) {
+	if m.lazy != nil {
+		m.lazy.options = append(m.lazy.options, option)
+		return
+	}
+	m.copySession()
+	option(m.session)
+}
+
+//Clone return a new Do with copied query settings, sharing session and collection
+//The clone never closes the shared session, Close the original one.
+func (m *Do) Clone() *Do {
+	do := *m
+	do.ownSession = false
+	do.pool = nil
+	do.pooled = nil
+	do.watchStop = nil
+	do.depth = 0
+	if m.lazy != nil {
+		lazy := *m.lazy
+		lazy.options = append([]func(s *mgo.Session){}, m.lazy.options...)
+		do.lazy = &lazy
+	}
+	if m.Query != nil {
+		do.Query = deepCopy(m.Query).(bson.M)
+	}
+	if m.Sort != nil {
+		do.Sort = append([]string{}, m.Sort...)
+	}
+	return &do
+}
+
+//WithDatabaseName return a clone of Do on collections of same names in database dbName
+//The session is shared, see Clone.
+func (m *Do) WithDatabaseName(dbName string) *Do {
+	do := m.Clone()
+	if do.lazy != nil {
+		do.lazy.dbName = dbName
+		return do
+	}
+	do.collection = m.collection.Database.Session.DB(dbName).C(m.collection.Name)
+	do.logCollection = m.logCollection.Database.Session.DB(dbName).C(m.logCollection.Name)
+	return do
+}
+
+//deepCopy copy nested maps and slices of query
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case bson.M:
+		c := bson.M{}
+		for k, val := range t {
+			c[k] = deepCopy(val)
+		}
+		return c
+	case map[string]interface{}:
+		c := map[string]interface{}{}
+		for k, val := range t {
+			c[k] = deepCopy(val)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(t))
+		for i, val := range t {
+			c[i] = deepCopy(val)
+		}
+		return c
+	case []bson.M:
+		c := make([]bson.M, len(t))
+		for i, val := range t {
+			c[i] = deepCopy(val).(bson.M)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+//ctxErr return error of bound context, nil if no context
+//It is checked first by every operation, so a lazy Do is connected here.
+func (m *Do) ctxErr() error {
+	if err := m.init(); err != nil {
+		return err
+	}
+	if m.ctx == nil {
+		return nil
+	}
+	return m.ctx.Err()
+}
+
+//Select keep only cols in results of following finds, "-col" to exclude col
+//Mixing included and excluded cols is not allowed by MongoDB, except "-_id".
+func (m *Do) Select(cols ...string) *Do {
+	if len(cols) == 0 {
+		m.selected = nil
+		return m
+	}
+	sCols := bson.M{}
+	for _, v := range cols {
+		if strings.HasPrefix(v, "-") {
+			sCols[v[1:]] = 0
+		} else {
+			sCols[v] = 1
+		}
+	}
+	m.selected = sCols
+	return m
+}
+
+//StoreSelect is Select, the projection is applied to FindAll, Get, GetByQ, ... until ClearSelect
+func (m *Do) StoreSelect(cols ...string) *Do {
+	return m.Select(cols...)
+}
+
+//ClearSelect remove projection of Select, following finds return whole records
+func (m *Do) ClearSelect() *Do {
+	m.selected = nil
+	return m
+}
+
+//SortAsc append fields to Sort in ascending order
+func (m *Do) SortAsc(fields ...string) *Do {
+	m.Sort = append(m.Sort, fields...)
+	return m
+}
+
+//SortDesc append fields to Sort in descending order, as "-field"
+func (m *Do) SortDesc(fields ...string) *Do {
+	for _, f := range fields {
+		m.Sort = append(m.Sort, "-"+f)
+	}
+	return m
+}
+
+//SortField is one field of Sort, descending if Desc
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+//AddSortField append f to Sort, as "-field" if descending
+func (m *Do) AddSortField(f SortField) *Do {
+	if f.Desc {
+		return m.SortDesc(f.Field)
+	}
+	return m.SortAsc(f.Field)
+}
+
+//Where AND query to existing conditions of Query and return Do for chain
+func (m *Do) Where(query bson.M) *Do {
+	if m.Query == nil {
+		m.Query = bson.M{}
+	}
+	m.Query["$and"] = append(andOf(m.Query), query)
+	return m
+}
+
+//CreatedBefore AND CreatedAt earlier than t to Query
+func (m *Do) CreatedBefore(t time.Time) *Do {
+	return m.Where(bson.M{"CreatedAt": bson.M{"$lt": t}})
+}
+
+//CreatedAfter AND CreatedAt later than t to Query
+func (m *Do) CreatedAfter(t time.Time) *Do {
+	return m.Where(bson.M{"CreatedAt": bson.M{"$gt": t}})
+}
+
+//UpdatedBefore AND UpdatedAt earlier than t to Query
+func (m *Do) UpdatedBefore(t time.Time) *Do {
+	return m.Where(bson.M{"UpdatedAt": bson.M{"$lt": t}})
+}
+
+//UpdatedAfter AND UpdatedAt later than t to Query
+func (m *Do) UpdatedAfter(t time.Time) *Do {
+	return m.Where(bson.M{"UpdatedAt": bson.M{"$gt": t}})
+}
+
+//OrQ add $or conditions to Query and return Do for chain
+//If Query already has $or, both are kept by moving the old one into $and.
+//IsRemoved exclusion of findQ is added to $and, so it applies to every
+//condition of $or.
+func (m *Do) OrQ(conditions []bson.M) *Do {
+	if m.Query == nil {
+		m.Query = bson.M{}
+	}
+	if v, found := m.Query["$or"]; found {
+		m.Query["$and"] = append(andOf(m.Query), bson.M{"$or": v})
+	}
+	m.Query["$or"] = conditions
+	return m
+}
+
+//CollectionNamer to be implemented by model using collection name other than its type name
+type CollectionNamer interface {
+	CollectionName() string
+}
+
 // Collection conduct mgo.Collection
 func Collection(s *mgo.Session, dbName string, m interface{}) *mgo.Collection {
-	cName := getModelName(m)
-	return s.DB(dbName).C(cName)
+	return s.DB(dbName).C(collectionName(m))
+}
+
+//collectionName from CollectionNamer, fallback to model name
+func collectionName(m interface{}) string {
+	if namer, ok := m.(CollectionNamer); ok {
+		return namer.CollectionName()
+	}
+	return getModelName(m)
 }
 
 //getModelName reflect string name from model
@@ -77,23 +449,97 @@ func getModelName(m interface{}) string {
 }
 
 //Create, generate objectId, upsert record with CreatedAt as Now
-func (m *Do) Create() error {
-	//generate new object Id
-	newId := bson.NewObjectId()
+func (m *Do) Create() (err error) {
+	defer m.trace("Create", m.enter(), &err)
+	err = m.beforeSave()
+	if err == nil {
+		err = m.ctxErr()
+	}
+	if err == nil {
+		// validate before stamp, so the model sees its own state
+		err = m.validate()
+	}
+	if err == nil {
+		// stamp once, so a retried upsert writes the same record
+		err = m.stampNew()
+	}
+	if err == nil {
+		err = m.retry(m.create)
+	}
+	m.afterSave(err)
+	if err == nil {
+		m.publish(CREATE)
+	}
+	return err
+}
+
+//stampNew generate new object Id, or set CustomId, and set CreatedAt and CreatedBy of model
+//Id of other types than bson.ObjectId should be assigned or set by CustomId,
+//otherwise every Create would write the record of zero Id.
+func (m *Do) stampNew() error {
 	id := reflect.ValueOf(m.model).Elem().FieldByName("Id")
-	id.Set(reflect.ValueOf(newId))
-	x := reflect.ValueOf(m.model).Elem().FieldByName("CreatedAt")
-	x.Set(reflect.ValueOf(time.Now()))
-	by := reflect.ValueOf(m.model).Elem().FieldByName("CreatedBy")
-	by.Set(reflect.ValueOf(m.Operator))
-	_, err := m.collection.Upsert(bson.M{"_id": id.Interface()}, bson.M{"$set": m.model})
+	if m.customId != nil {
+		if !reflect.TypeOf(m.customId).AssignableTo(id.Type()) {
+			return errors.New("Custom id should be of the type of Id.")
+		}
+	} else if id.Type() != reflect.TypeOf(bson.ObjectId("")) && id.IsZero() {
+		return errors.New("Id should be assigned or set by CustomId.")
+	}
+	m.stampCreate(m.model)
+	if m.customId != nil {
+		id.Set(reflect.ValueOf(m.customId))
+	}
+	return nil
+}
+
+//create do the Create without hooks, model is validated and stamped by Create
+func (m *Do) create() error {
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	id := reflect.ValueOf(m.model).Elem().FieldByName("Id")
+	m.uncache()
+	_, err := m.collection.Upsert(m.tenantQ(bson.M{"_id": id.Interface()}), bson.M{"$set": m.tenantDoc(m.model)})
+	if err == nil {
+		m.customId = nil
+	}
 
 	return err
 }
 
+//CustomId use id as Id of next Create instead of a new ObjectId, e.g. UUID string or int
+//Id field of model should be of the type of id, the one of BaseModel is bson.ObjectId.
+func (m *Do) CustomId(id interface{}) *Do {
+	m.customId = id
+	return m
+}
+
+//CreateIfNotExists insert record without overwrite, duplicate _id returns DoError of mgo.IsDup Err
+//Id, CreatedAt and CreatedBy are stamped as Create, CustomId is used if set.
+//Otherwise a pre-assigned Id is kept.
+func (m *Do) CreateIfNotExists() (err error) {
+	defer m.trace("CreateIfNotExists", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	id := reflect.ValueOf(m.model).Elem().FieldByName("Id")
+	oldId := id.Interface()
+	if err := m.stampNew(); err != nil {
+		return err
+	}
+	if m.customId == nil && oldId != reflect.Zero(id.Type()).Interface() {
+		id.Set(reflect.ValueOf(oldId))
+	}
+	err = m.collection.Insert(m.tenantDoc(m.model))
+	if err == nil {
+		m.customId = nil
+	}
+	return err
+}
+
 //CreateWithLog record log for creation
-func (m *Do) CreateWithLog() error {
-	var err error
+func (m *Do) CreateWithLog() (err error) {
+	defer m.trace("CreateWithLog", m.enter(), &err)
 	err = m.Create()
 	if err != nil {
 		return err
@@ -107,7 +553,24 @@ func (m *Do) CreateWithLog() error {
 }
 
 //Save method, upsert record with UpdatedAt as now
-func (m *Do) Save() error {
+func (m *Do) Save() (err error) {
+	defer m.trace("Save", m.enter(), &err)
+	err = m.beforeSave()
+	if err == nil {
+		err = m.retry(m.save)
+	}
+	m.afterSave(err)
+	return err
+}
+
+//save do the Save without hooks
+func (m *Do) save() error {
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if err := m.validate(); err != nil {
+		return err
+	}
 	id := reflect.ValueOf(m.model).Elem().FieldByName("Id")
 	x := reflect.ValueOf(m.model).Elem().FieldByName("UpdatedAt")
 	x.Set(reflect.ValueOf(time.Now()))
@@ -115,7 +578,7 @@ func (m *Do) Save() error {
 	by.Set(reflect.ValueOf(m.Operator))
 	// check IsLocked flag
 	record := map[string]interface{}{}
-	m.collection.FindId(id.Interface()).Select(bson.M{"IsLocked": 1}).One(&record)
+	m.collection.Find(m.tenantQ(bson.M{"_id": id.Interface()})).Select(bson.M{"IsLocked": 1}).One(&record)
 	if record != nil {
 		if v, found := record["IsLocked"]; found {
 			if v.(bool) {
@@ -124,13 +587,14 @@ func (m *Do) Save() error {
 		}
 	}
 
-	_, err := m.collection.Upsert(bson.M{"_id": id.Interface()}, bson.M{"$set": m.model})
+	m.uncache()
+	_, err := m.collection.Upsert(m.tenantQ(bson.M{"_id": id.Interface()}), bson.M{"$set": m.tenantDoc(m.model)})
 	return err
 }
 
 //SaveWithLog save record and inset a new changelog record
-func (m *Do) SaveWithLog() error {
-	var err error
+func (m *Do) SaveWithLog() (err error) {
+	defer m.trace("SaveWithLog", m.enter(), &err)
 	err = m.Save()
 	if err != nil {
 		return err
@@ -139,21 +603,41 @@ func (m *Do) SaveWithLog() error {
 	if err != nil {
 		return err
 	}
+	m.publish(UPDATE)
 	return nil
 }
 
 //Erase is hard delete according ID
-func (m *Do) Erase() error {
+func (m *Do) Erase() (err error) {
+	defer m.trace("Erase", m.enter(), &err)
+	err = m.beforeErase()
+	if err == nil {
+		err = m.retry(m.erase)
+	}
+	m.afterErase(err)
+	return err
+}
+
+//erase do the Erase without hooks
+func (m *Do) erase() error {
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	//hard delete record
 	id := reflect.ValueOf(m.model).Elem().FieldByName("Id")
-	err := m.collection.RemoveId(id.Interface())
+	m.uncache()
+	err := m.collection.Remove(m.tenantQ(bson.M{"_id": id.Interface()}))
 	return err
 }
 
 //EraseWithLog, hard delete record and insert a chagnelog
-func (m *Do) EraseWithLog() error {
+func (m *Do) EraseWithLog() (err error) {
+	defer m.trace("EraseWithLog", m.enter(), &err)
 	// hard delete record
-	err := m.Erase()
+	err = m.Erase()
+	if err != nil {
+		return err
+	}
 
 	// Save log
 	err = m.saveLog(ERASE)
@@ -161,22 +645,41 @@ func (m *Do) EraseWithLog() error {
 		return err
 	}
 
-	return err
+	m.publish(ERASE)
+	return nil
 }
 
 // Delete is softe delete
-func (m *Do) Delete() error {
+func (m *Do) Delete() (err error) {
+	defer m.trace("Delete", m.enter(), &err)
+	err = m.beforeDelete()
+	if err == nil {
+		err = m.retry(m.delete)
+	}
+	m.afterDelete(err)
+	return err
+}
+
+//delete do the Delete without hooks
+func (m *Do) delete() error {
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if err := m.validate(); err != nil {
+		return err
+	}
+	sd := m.softDeleteConfig()
 	id := reflect.ValueOf(m.model).Elem().FieldByName("Id")
-	x := reflect.ValueOf(m.model).Elem().FieldByName("RemovedAt")
+	x := reflect.ValueOf(m.model).Elem().FieldByName(sd.DeletedAt)
 	x.Set(reflect.ValueOf(time.Now()))
-	by := reflect.ValueOf(m.model).Elem().FieldByName("RemovedBy")
+	by := reflect.ValueOf(m.model).Elem().FieldByName(sd.DeletedBy)
 	by.Set(reflect.ValueOf(m.Operator))
-	removed := reflect.ValueOf(m.model).Elem().FieldByName("IsRemoved")
+	removed := reflect.ValueOf(m.model).Elem().FieldByName(sd.DeletedFlag)
 	removed.Set(reflect.ValueOf(true))
 
 	// check IsLocked flag
 	record := map[string]interface{}{}
-	m.collection.FindId(id.Interface()).Select(bson.M{"IsLocked": 1}).One(&record)
+	m.collection.Find(m.tenantQ(bson.M{"_id": id.Interface()})).Select(bson.M{"IsLocked": 1}).One(&record)
 	if record != nil {
 		if v, found := record["IsLocked"]; found {
 			if v.(bool) {
@@ -185,13 +688,15 @@ func (m *Do) Delete() error {
 		}
 	}
 
-	_, err := m.collection.Upsert(bson.M{"_id": id.Interface()}, bson.M{"$set": m.model})
+	m.uncache()
+	_, err := m.collection.Upsert(m.tenantQ(bson.M{"_id": id.Interface()}), bson.M{"$set": m.tenantDoc(m.model)})
 	return err
 }
 
 //DeleteWithLog
-func (m *Do) DeleteWithLog() error {
-	err := m.saveLog(DELETE)
+func (m *Do) DeleteWithLog() (err error) {
+	defer m.trace("DeleteWithLog", m.enter(), &err)
+	err = m.saveLog(DELETE)
 	if err != nil {
 		return err
 	}
@@ -199,12 +704,74 @@ func (m *Do) DeleteWithLog() error {
 	if err != nil {
 		return err
 	}
+	m.publish(DELETE)
 	return nil
 
 }
 
+//Restore undo soft delete, clear IsRemoved, RemovedAt and RemovedBy
+func (m *Do) Restore() (err error) {
+	defer m.trace("Restore", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	id := reflect.ValueOf(m.model).Elem().FieldByName("Id")
+	sd := m.softDeleteConfig()
+	removed := reflect.ValueOf(m.model).Elem().FieldByName(sd.DeletedFlag)
+	removed.Set(reflect.ValueOf(false))
+	x := reflect.ValueOf(m.model).Elem().FieldByName(sd.DeletedAt)
+	x.Set(reflect.ValueOf(time.Time{}))
+	by := reflect.ValueOf(m.model).Elem().FieldByName(sd.DeletedBy)
+	by.Set(reflect.ValueOf(""))
+	x = reflect.ValueOf(m.model).Elem().FieldByName("UpdatedAt")
+	x.Set(reflect.ValueOf(time.Now()))
+	by = reflect.ValueOf(m.model).Elem().FieldByName("UpdatedBy")
+	by.Set(reflect.ValueOf(m.Operator))
+
+	// check IsLocked flag
+	record := map[string]interface{}{}
+	m.collection.Find(m.tenantQ(bson.M{"_id": id.Interface()})).Select(bson.M{"IsLocked": 1}).One(&record)
+	if record != nil {
+		if v, found := record["IsLocked"]; found {
+			if v.(bool) {
+				return errors.New("Record locked for restore.")
+			}
+		}
+	}
+
+	m.uncache()
+	_, err = m.collection.Upsert(m.tenantQ(bson.M{"_id": id.Interface()}), bson.M{"$set": m.tenantDoc(m.model)})
+	if err != nil {
+		return err
+	}
+	// zero values are omitted by $set, unset them explicitly
+	unset := bson.M{sd.DeletedFlag: 1, sd.DeletedAt: 1, sd.DeletedBy: 1}
+	if m.softDelete == nil {
+		unset["is_removed"] = 1
+	}
+	err = m.collection.Update(m.tenantQ(bson.M{"_id": id.Interface()}), bson.M{"$unset": unset})
+	return err
+}
+
+//RestoreWithLog restore record and insert a changelog
+func (m *Do) RestoreWithLog() (err error) {
+	defer m.trace("RestoreWithLog", m.enter(), &err)
+	err = m.Restore()
+	if err != nil {
+		return err
+	}
+	err = m.saveLog(RESTORE)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 //saveLog just copy a record to Changlog
-func (m *Do) saveLog(operation string) error {
+func (m *Do) saveLog(operation string) (err error) {
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	//read current record
 	//var record interface{}
 	//recordId := reflect.ValueOf(m.model).Elem().FieldByName("Id").Interface().(bson.ObjectId)
@@ -213,19 +780,30 @@ func (m *Do) saveLog(operation string) error {
 	//return err
 	//}
 
-	id := reflect.ValueOf(m.model).Elem().FieldByName("Id")
+	cl := m.newLog(m.model, operation)
+	_, err = m.logCollection.Upsert(bson.M{"_id": cl.Id}, bson.M{"$set": cl})
+	return err
+}
 
+//newLog conduct a ChangeLog of model for operation
+func (m *Do) newLog(model interface{}, operation string) *ChangeLog {
+	id := reflect.ValueOf(model).Elem().FieldByName("Id")
+	return m.newLogOf(id.Interface(), model, operation)
+}
+
+//newLogOf conduct a ChangeLog of record id with value
+func (m *Do) newLogOf(id interface{}, value interface{}, operation string) *ChangeLog {
 	cl := new(ChangeLog)
 	cl.Id = bson.NewObjectId()
 	cl.CreatedBy = m.Operator
 	cl.CreatedAt = time.Now()
 	cl.ChangeReason = m.Reason
 	cl.Operation = operation
-	cl.ModelObjId = id.Interface().(bson.ObjectId)
+	cl.ModelObjId = id
 	cl.ModelName = getModelName(m.model)
-	cl.ModelValue = m.model
-	_, err := m.logCollection.Upsert(bson.M{"_id": cl.Id}, bson.M{"$set": cl})
-	return err
+	cl.ModelValue = value
+	cl.TenantId = m.tenantId
+	return cl
 }
 
 // ---------- General mgo functions -----------
@@ -237,42 +815,74 @@ func (m *Do) Q() *mgo.Query {
 
 //findQ conduct mgo.Query, skip IsRemoved: true
 func (m *Do) findQ() *mgo.Query {
-	var query *mgo.Query
-	//do not query removed value
-	rmQ := []interface{}{bson.M{"is_removed": bson.M{"$ne": true}}, bson.M{"IsRemoved": bson.M{"$ne": true}}}
-	if m.Query != nil {
-		if v, found := m.Query["$and"]; !found {
-			m.Query["$and"] = rmQ
-		} else {
-			m.Query["$and"] = append(v.([]interface{}), rmQ...)
-		}
-	} else {
-		m.Query = bson.M{"$and": rmQ}
-	}
+	// connect lazy Do for Q and Iter, operations did in ctxErr
+	m.init()
+	//do not query removed value, Query is kept for SoftDeletedCount, CountAll, ...
+	return m.findWithQ(m.notRemovedQ())
+}
 
-	query = m.collection.Find(m.Query)
-	//sort
-	if m.Sort != nil {
-		query = query.Sort(m.Sort...)
-	}
+//notRemovedQ return a copy of m.Query with IsRemoved: true excluded
+func (m *Do) notRemovedQ() bson.M {
+	return m.notRemoved(m.Query)
+}
 
-	//skip
-	if m.Skip != 0 {
-		query = query.Skip(m.Skip)
+//notRemoved return a copy of query with IsRemoved: true excluded
+//Legacy is_removed is checked as well unless SoftDeleteConfig is set.
+func (m *Do) notRemoved(query bson.M) bson.M {
+	rmQ := []interface{}{bson.M{m.softDeleteConfig().DeletedFlag: bson.M{"$ne": true}}}
+	if m.softDelete == nil {
+		rmQ = []interface{}{bson.M{"is_removed": bson.M{"$ne": true}}, bson.M{"IsRemoved": bson.M{"$ne": true}}}
 	}
+	if m.tenantId != "" {
+		rmQ = append(rmQ, bson.M{TenantField: m.tenantId})
+	}
+	q := bson.M{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q["$and"] = append(andOf(q), rmQ...)
+	return q
+}
 
-	//limit
-	if m.Limit != 0 {
-		query = query.Limit(m.Limit)
+//andOf copy conditions of $and in query to a new []interface{}
+//$and set by caller may be []bson.M or any other slice, its conditions are kept.
+func andOf(query bson.M) []interface{} {
+	v := reflect.ValueOf(query["$and"])
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []interface{}{}
 	}
-	return query
+	and := make([]interface{}, v.Len())
+	for i := range and {
+		and[i] = v.Index(i).Interface()
+	}
+	return and
 }
 
 //findIncludeRemovedQ conduct mgo.Query, including marked as removed: isRemoved: true
 func (m *Do) findIncludeRemovedQ() *mgo.Query {
+	return m.findWithQ(m.tenantQ(m.Query))
+}
+
+//refresh refresh a private copy of session before read if RefreshBeforeRead
+func (m *Do) refresh() {
+	if !m.RefreshBeforeRead {
+		return
+	}
+	m.copySession()
+	m.session.Refresh()
+}
+
+//find conduct mgo.Query of q as is, every read goes through it for RefreshBeforeRead
+func (m *Do) find(q bson.M) *mgo.Query {
+	m.refresh()
+	return m.collection.Find(q)
+}
+
+//findWithQ conduct mgo.Query of q with Sort, Skip and Limit applied
+func (m *Do) findWithQ(q bson.M) *mgo.Query {
 	var query *mgo.Query
 
-	query = m.collection.Find(m.Query)
+	query = m.find(q)
 	//sort
 	if m.Sort != nil {
 		query = query.Sort(m.Sort...)
@@ -287,6 +897,11 @@ func (m *Do) findIncludeRemovedQ() *mgo.Query {
 	if m.Limit != 0 {
 		query = query.Limit(m.Limit)
 	}
+
+	//projection
+	if m.selected != nil {
+		query = query.Select(m.selected)
+	}
 	return query
 }
 
@@ -299,56 +914,277 @@ func (m *Do) findByIdQ() *mgo.Query {
 
 //Count
 func (m *Do) Count() int64 {
+	defer m.trace("Count", m.enter(), nil)
+	if m.ctxErr() != nil {
+		return 0
+	}
 	query := m.findQ()
 	count, _ := query.Count()
 	return int64(count)
 }
 
+//CountAll count records of query, including marked as removed
+func (m *Do) CountAll() int64 {
+	defer m.trace("CountAll", m.enter(), nil)
+	if m.ctxErr() != nil {
+		return 0
+	}
+	count, _ := m.find(m.tenantQ(m.Query)).Count()
+	return int64(count)
+}
+
+//SoftDeletedCount count records of query marked as removed, e.g. records in trash
+func (m *Do) SoftDeletedCount() int64 {
+	defer m.trace("SoftDeletedCount", m.enter(), nil)
+	if m.ctxErr() != nil {
+		return 0
+	}
+	q := bson.M{}
+	for k, v := range m.Query {
+		q[k] = v
+	}
+	q["$and"] = append(andOf(q), m.removedQ())
+	count, _ := m.find(m.tenantQ(q)).Count()
+	return int64(count)
+}
+
+//Exists check if any record matches query, skip IsRemoved:true
+func (m *Do) Exists() (_ bool, err error) {
+	defer m.trace("Exists", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return false, err
+	}
+	count, err := m.findQ().Limit(1).Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+//RecordExists check if record of model _id exists, skip IsRemoved:true, only _id is read
+func (m *Do) RecordExists() (_ bool, err error) {
+	defer m.trace("RecordExists", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return false, err
+	}
+	var record bson.M
+	err = m.findByIdQ().Select(bson.M{"_id": 1}).One(&record)
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//Explain return query plan of query, skip IsRemoved:true
+func (m *Do) Explain() (_ bson.M, err error) {
+	defer m.trace("Explain", m.enter(), &err)
+	result := bson.M{}
+	if err := m.ctxErr(); err != nil {
+		return result, err
+	}
+	err = m.findQ().Explain(&result)
+	return result, err
+}
+
 //---------retrieve functions
 // FindAll except removed, i is interface address
-func (m *Do) FindAll(i interface{}) error {
-	query := m.findQ()
-	err := query.All(i)
-	return err
+func (m *Do) FindAll(i interface{}) (err error) {
+	defer m.trace("FindAll", m.enter(), &err)
+	return m.retry(func() error {
+		if err := m.ctxErr(); err != nil {
+			return err
+		}
+		query := m.findQ()
+		err := query.All(i)
+		return err
+	})
+}
+
+//FindAllSorted FindAll sorted by sortFields for this call only, Sort is kept unchanged
+func (m *Do) FindAllSorted(i interface{}, sortFields ...string) error {
+	sort := m.Sort
+	m.Sort = sortFields
+	defer func() { m.Sort = sort }()
+	return m.FindAll(i)
+}
+
+//GetLatest get last created record of query to model, Sort is kept unchanged
+func (m *Do) GetLatest() error {
+	return m.getSorted("-CreatedAt")
+}
+
+//GetOldest get first created record of query to model, Sort is kept unchanged
+func (m *Do) GetOldest() error {
+	return m.getSorted("CreatedAt")
+}
+
+func (m *Do) getSorted(sortFields ...string) error {
+	sort := m.Sort
+	m.Sort = sortFields
+	defer func() { m.Sort = sort }()
+	return m.GetByQ()
 }
 
 // FindAll except removed, i is interface address
-func (m *Do) FindAllIncludeRemoved(i interface{}) error {
+func (m *Do) FindAllIncludeRemoved(i interface{}) (err error) {
+	defer m.trace("FindAllIncludeRemoved", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	query := m.findIncludeRemovedQ()
-	err := query.All(i)
+	err = query.All(i)
 	return err
 }
 
 //Get will retrieve by _id
-func (m *Do) Get() error {
+func (m *Do) Get() (err error) {
+	defer m.trace("Get", m.enter(), &err)
+	return m.retry(func() error {
+		if err := m.ctxErr(); err != nil {
+			return err
+		}
+		if m.fromCache() {
+			return nil
+		}
+		query := m.findByIdQ()
+		err := query.One(m.model)
+		if err == nil {
+			m.toCache()
+		}
+		return err
+	})
+}
+
+//Refresh reload record of model _id into target, into model if target is nil, skip IsRemoved:true
+func (m *Do) Refresh(target interface{}) (err error) {
+	defer m.trace("Refresh", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if target == nil {
+		target = m.model
+	}
+	err = m.findByIdQ().One(target)
+	return err
+}
+
+//FindByIds find records of ids, skip IsRemoved:true, Query is not used
+func (m *Do) FindByIds(ids []bson.ObjectId, result interface{}) (err error) {
+	defer m.trace("FindByIds", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	q := m.notRemoved(bson.M{"_id": bson.M{"$in": ids}})
+	err = m.findWithQ(q).All(result)
+	return err
+}
+
+//GetMany find records of ids matching Query as well, skip IsRemoved:true, with Sort, Skip and Limit
+func (m *Do) GetMany(ids []bson.ObjectId, result interface{}) (err error) {
+	defer m.trace("GetMany", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	q := bson.M{"_id": bson.M{"$in": ids}}
+	if len(m.Query) > 0 {
+		q = bson.M{"$and": []interface{}{m.Query, q}}
+	}
+	err = m.findWithQ(m.notRemoved(q)).All(result)
+	return err
+}
+
+//CopyTo retrieve record of model _id into target, e.g. a DTO with fewer fields
+func (m *Do) CopyTo(target interface{}) (err error) {
+	defer m.trace("CopyTo", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	query := m.findByIdQ()
-	err := query.One(m.model)
+	err = query.One(target)
+	return err
+}
+
+//GetIncludeRemoved will retrieve by _id, including marked as removed
+func (m *Do) GetIncludeRemoved() (err error) {
+	defer m.trace("GetIncludeRemoved", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	id := reflect.ValueOf(m.model).Elem().FieldByName("Id").Interface()
+	m.Query = bson.M{"_id": id}
+	query := m.findIncludeRemovedQ()
+	err = query.One(m.model)
 	return err
 }
 
 //GetByQ get first one based on query, model will be updated
-func (m *Do) GetByQ() error {
+func (m *Do) GetByQ() (err error) {
+	defer m.trace("GetByQ", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	query := m.findQ()
-	err := query.One(m.model)
+	err = query.One(m.model)
 	return err
 }
 
 //QueryIncludeRemoved get first one based on query include isRemoved: true, model will be updated
-func (m *Do) QueryIncludeRemoved() error {
+func (m *Do) QueryIncludeRemoved() (err error) {
+	defer m.trace("QueryIncludeRemoved", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	query := m.findIncludeRemovedQ()
-	err := query.One(m.model)
+	err = query.One(m.model)
 	return err
 }
 
 //Fetch match result to a structure
-func (m *Do) FetchByQ(record interface{}) error {
+func (m *Do) FetchByQ(record interface{}) (err error) {
+	defer m.trace("FetchByQ", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	query := m.findQ()
-	err := query.One(record)
+	err = query.One(record)
+	return err
+}
+
+//FindOne decode first record of query to result, model is not used, same as FetchByQ
+func (m *Do) FindOne(result interface{}) error {
+	return m.FetchByQ(result)
+}
+
+//FindRaw find all records of query as is
+//It bypasses every filter of Do: the IsRemoved guard, Query, Sort, Skip, Limit and Select.
+func (m *Do) FindRaw(query bson.M, result interface{}) (err error) {
+	defer m.trace("FindRaw", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	err = m.find(m.tenantQ(query)).All(result)
+	return err
+}
+
+//GetRaw get first record of query as is, bypassing every filter of Do like FindRaw
+func (m *Do) GetRaw(query bson.M, result interface{}) (err error) {
+	defer m.trace("GetRaw", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	err = m.find(m.tenantQ(query)).One(result)
 	return err
 }
 
 //Select query and select columns
-func (m *Do) FindWithSelect(i interface{}, cols []string) error {
+func (m *Do) FindWithSelect(i interface{}, cols []string) (err error) {
+	defer m.trace("FindWithSelect", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	sCols := bson.M{}
 	for _, v := range cols {
 		if strings.HasPrefix(v, "-") {
@@ -359,18 +1195,26 @@ func (m *Do) FindWithSelect(i interface{}, cols []string) error {
 		}
 	}
 	query := m.findQ().Select(sCols)
-	err := query.All(i)
+	err = query.All(i)
 	return err
 }
 
 //Distinct
-func (m *Do) Distinct(key string, i interface{}) error {
-	err := m.findQ().Distinct(key, i)
+func (m *Do) Distinct(key string, i interface{}) (err error) {
+	defer m.trace("Distinct", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	err = m.findQ().Distinct(key, i)
 	return err
 }
 
 //GetWithSelect, limit cols
-func (m *Do) GetWithSelect(cols []string) error {
+func (m *Do) GetWithSelect(cols []string) (err error) {
+	defer m.trace("GetWithSelect", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	sCols := bson.M{}
 	for _, v := range cols {
 		if strings.HasPrefix(v, "-") {
@@ -381,19 +1225,96 @@ func (m *Do) GetWithSelect(cols []string) error {
 		}
 	}
 	query := m.findByIdQ().Select(sCols)
-	err := query.One(m.model)
+	err = query.One(m.model)
 	return err
 }
 
+//FindWithExclude query and exclude columns, _id is kept unless excluded
+func (m *Do) FindWithExclude(i interface{}, cols []string) (err error) {
+	defer m.trace("FindWithExclude", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	query := m.findQ().Select(excludeCols(cols))
+	err = query.All(i)
+	return err
+}
+
+//GetWithExclude, exclude cols
+func (m *Do) GetWithExclude(cols []string) (err error) {
+	defer m.trace("GetWithExclude", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	query := m.findByIdQ().Select(excludeCols(cols))
+	err = query.One(m.model)
+	return err
+}
+
+//FindWithHint query with index of indexHint, "field" or []string{"a", "-b"} as index key
+func (m *Do) FindWithHint(i interface{}, indexHint interface{}) (err error) {
+	defer m.trace("FindWithHint", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	key, err := hintKey(indexHint)
+	if err != nil {
+		return err
+	}
+	err = m.findQ().Hint(key...).All(i)
+	return err
+}
+
+//GetWithHint get first record of query to model with index of indexHint
+func (m *Do) GetWithHint(indexHint interface{}) (err error) {
+	defer m.trace("GetWithHint", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	key, err := hintKey(indexHint)
+	if err != nil {
+		return err
+	}
+	err = m.findQ().Hint(key...).One(m.model)
+	return err
+}
+
+//hintKey conduct index key of hint, mgo only accepts key fields
+func hintKey(indexHint interface{}) ([]string, error) {
+	switch h := indexHint.(type) {
+	case string:
+		return []string{h}, nil
+	case []string:
+		return h, nil
+	case mgo.Index:
+		return h.Key, nil
+	}
+	return nil, errors.New("Index hint should be string, []string or mgo.Index.")
+}
+
+//excludeCols conduct exclusion projection
+func excludeCols(cols []string) bson.M {
+	sCols := bson.M{}
+	for _, v := range cols {
+		sCols[v] = 0
+	}
+	return sCols
+}
+
 //Erase all is hard Delete with raw condition (no predefined skip IsRemoved:true)
-func (m *Do) EraseAll() error {
-	_, err := m.collection.RemoveAll(m.Query)
+func (m *Do) EraseAll() (err error) {
+	defer m.trace("EraseAll", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	_, err = m.collection.RemoveAll(m.tenantQ(m.Query))
 	return err
 }
 
 // Erase all with log
-func (m *Do) EraseAllWithLog() error {
-	err := m.EraseAll()
+func (m *Do) EraseAllWithLog() (err error) {
+	defer m.trace("EraseAllWithLog", m.enter(), &err)
+	err = m.EraseAll()
 
 	// Save log
 	err = m.saveLog(ERASE)
@@ -404,12 +1325,52 @@ func (m *Do) EraseAllWithLog() error {
 	return err
 }
 
+//EraseByQ hard delete records of query, skip IsRemoved:true
+func (m *Do) EraseByQ() (err error) {
+	defer m.trace("EraseByQ", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	_, err = m.collection.RemoveAll(m.notRemovedQ())
+	return err
+}
+
+//EraseByQWithLog hard delete records of query and insert one changelog per record
+func (m *Do) EraseByQWithLog() (err error) {
+	defer m.trace("EraseByQWithLog", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	var records []bson.M
+	err = m.collection.Find(m.notRemovedQ()).All(&records)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	// erase exactly the logged records
+	ids := make([]interface{}, len(records))
+	for i, r := range records {
+		ids[i] = r["_id"]
+	}
+	_, err = m.collection.RemoveAll(bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	return m.saveLogs(records, ERASE)
+}
+
 //DirectSave method, upsert record without set UpdatedBy and UpdatedAt
-func (m *Do) DirectSave() error {
+func (m *Do) DirectSave() (err error) {
+	defer m.trace("DirectSave", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
 	id := reflect.ValueOf(m.model).Elem().FieldByName("Id")
 	// check IsLocked flag
 	record := map[string]interface{}{}
-	m.collection.FindId(id.Interface()).Select(bson.M{"IsLocked": 1}).One(&record)
+	m.collection.Find(m.tenantQ(bson.M{"_id": id.Interface()})).Select(bson.M{"IsLocked": 1}).One(&record)
 	if record != nil {
 		if v, found := record["IsLocked"]; found {
 			if v.(bool) {
@@ -418,13 +1379,14 @@ func (m *Do) DirectSave() error {
 		}
 	}
 
-	_, err := m.collection.Upsert(bson.M{"_id": id.Interface()}, bson.M{"$set": m.model})
+	m.uncache()
+	_, err = m.collection.Upsert(m.tenantQ(bson.M{"_id": id.Interface()}), bson.M{"$set": m.tenantDoc(m.model)})
 	return err
 }
 
 //DirectSaveWithLog save record and inset a new changelog record
-func (m *Do) DirectSaveWithLog() error {
-	var err error
+func (m *Do) DirectSaveWithLog() (err error) {
+	defer m.trace("DirectSaveWithLog", m.enter(), &err)
 	err = m.DirectSave()
 	if err != nil {
 		return err
@@ -435,3 +1397,115 @@ func (m *Do) DirectSaveWithLog() error {
 	}
 	return nil
 }
+
+//FindAndModify apply change to first record of query atomically, skip IsRemoved:true
+//If change.Update is a $set map, UpdatedAt and UpdatedBy are set as well.
+//errors.Is(err, mgo.ErrNotFound) when nothing matched.
+func (m *Do) FindAndModify(change mgo.Change, result interface{}) (err error) {
+	defer m.trace("FindAndModify", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if update, ok := change.Update.(bson.M); ok {
+		if set, ok := update["$set"].(bson.M); ok {
+			newSet := bson.M{}
+			for k, v := range set {
+				newSet[k] = v
+			}
+			newSet["UpdatedAt"] = time.Now()
+			newSet["UpdatedBy"] = m.Operator
+
+			newUpdate := bson.M{}
+			for k, v := range update {
+				newUpdate[k] = v
+			}
+			newUpdate["$set"] = newSet
+			change.Update = newUpdate
+		}
+	}
+	_, err = m.findQ().Apply(change, result)
+	return err
+}
+
+//Iter return cursor of query, skip IsRemoved:true
+func (m *Do) Iter() *mgo.Iter {
+	return m.findQ().Iter()
+}
+
+//FindIterWithBatchSize return cursor of query fetching batchSize records per round-trip, skip IsRemoved:true
+func (m *Do) FindIterWithBatchSize(batchSize int) *mgo.Iter {
+	return m.findQ().Batch(batchSize).Iter()
+}
+
+//ForEach decode records one by one into a new model and call fn with it
+//Iteration stops on first error of fn, which is returned.
+func (m *Do) ForEach(fn func(interface{}) error) (err error) {
+	defer m.trace("ForEach", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	typ := reflect.TypeOf(m.model)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	iter := m.Iter()
+	record := reflect.New(typ).Interface()
+	for iter.Next(record) {
+		if err := fn(record); err != nil {
+			iter.Close()
+			return err
+		}
+		if err := m.ctxErr(); err != nil {
+			iter.Close()
+			return err
+		}
+		record = reflect.New(typ).Interface()
+	}
+	return iter.Close()
+}
+
+//FindStream decode records of query as bson.M to the first channel in a goroutine, skip IsRemoved:true
+//batchSize records are fetched per round-trip. An error is sent to the second
+//channel before both are closed. Read the records until closed, or cancel the
+//context of Do, otherwise the goroutine is blocked.
+func (m *Do) FindStream(batchSize int) (<-chan interface{}, <-chan error) {
+	records := make(chan interface{})
+	errc := make(chan error, 1)
+	if err := m.ctxErr(); err != nil {
+		errc <- m.wrapError("FindStream", err)
+		close(records)
+		close(errc)
+		return records, errc
+	}
+	var done <-chan struct{}
+	if m.ctx != nil {
+		done = m.ctx.Done()
+	}
+	iter := m.findQ().Batch(batchSize).Iter()
+	go func() {
+		var err error
+		defer func() {
+			if err != nil {
+				errc <- m.wrapError("FindStream", err)
+			}
+			close(records)
+			close(errc)
+		}()
+		for {
+			var record bson.M
+			if !iter.Next(&record) {
+				break
+			}
+			select {
+			case records <- record:
+			case <-done:
+				iter.Close()
+				err = m.ctx.Err()
+				return
+			}
+		}
+		err = iter.Close()
+	}()
+	return records, errc
+}
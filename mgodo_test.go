@@ -1,11 +1,18 @@
 package mgodo
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	mgo "github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
+
+	"mgodo/cache"
 )
 
 type User struct {
@@ -69,3 +76,678 @@ func TestFindAll(t *testing.T) {
 	op.FindAll(&users)
 	fmt.Println(users)
 }
+
+func TestCancelledContext(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	user := new(User)
+	user.Id = bson.NewObjectId()
+	op := NewDo(s, dbName, user).WithContext(ctx)
+	defer op.Close()
+
+	if err := op.Create(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Create expect %v, got %v", context.Canceled, err)
+	}
+	var users []*User
+	if err := op.FindAll(&users); !errors.Is(err, context.Canceled) {
+		t.Errorf("FindAll expect %v, got %v", context.Canceled, err)
+	}
+	if err := op.Get(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get expect %v, got %v", context.Canceled, err)
+	}
+
+	// expired deadline
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	time.Sleep(2 * time.Millisecond)
+	op = NewDo(s, dbName, user).WithContext(ctx)
+	defer op.Close()
+	if err := op.Save(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Save expect %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestWriteConcern(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	user := new(User)
+	user.Name = "Fire"
+	op := NewDo(s, dbName, user).WithWriteConcern(0, false, 0)
+	defer op.Close()
+	if op.session.Safe() != nil {
+		t.Errorf("w=0 should be unacknowledged write")
+	}
+	if err := op.Create(); err != nil {
+		t.Errorf("Err during create: %v", err)
+	}
+
+	user2 := new(User)
+	user2.Name = "Journal"
+	op2 := NewDo(s, dbName, user2).WithWriteConcern(1, true, time.Second)
+	defer op2.Close()
+	if safe := op2.session.Safe(); safe == nil || safe.W != 1 || !safe.J {
+		t.Errorf("w=1, j=true expected, got %+v", safe)
+	}
+	if err := op2.Create(); err != nil {
+		t.Errorf("Err during create: %v", err)
+	}
+	if s.Safe() != nil && s.Safe().J {
+		t.Errorf("write concern should not change original session")
+	}
+}
+
+type Account struct {
+	BaseModel    `bson:",inline"`
+	Name         string `bson:"name,omitempty"`
+	PasswordHash string `bson:"password_hash,omitempty"`
+}
+
+func TestGetWithExclude(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	account := new(Account)
+	account.Name = "Tom"
+	account.PasswordHash = "secret"
+	op := NewDo(s, dbName, account)
+	if err := op.Create(); err != nil {
+		t.Fatalf("Err during create: %v", err)
+	}
+
+	got := new(Account)
+	got.Id = account.Id
+	op = NewDo(s, dbName, got)
+	if err := op.GetWithExclude([]string{"password_hash"}); err != nil {
+		t.Fatalf("Err during get: %v", err)
+	}
+	if got.PasswordHash != "" {
+		t.Errorf("password_hash should be excluded, got %q", got.PasswordHash)
+	}
+	if got.Id != account.Id || got.Name != "Tom" {
+		t.Errorf("other fields should be kept, got %+v", got)
+	}
+}
+
+func TestEnsureIndex(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	op := NewDo(s, dbName, new(User))
+	index := mgo.Index{Key: []string{"name", "-age"}}
+	if err := op.EnsureIndex(index); err != nil {
+		t.Fatalf("Err during ensure index: %v", err)
+	}
+	if err := op.EnsureIndex(index); err != nil {
+		t.Errorf("EnsureIndex should be idempotent, got %v", err)
+	}
+	indexes, err := op.ListIndexes()
+	if err != nil {
+		t.Fatalf("Err during list indexes: %v", err)
+	}
+	fmt.Println(indexes)
+	if err := op.DropIndex(index.Key); err != nil {
+		t.Errorf("Err during drop index: %v", err)
+	}
+}
+
+type Token struct {
+	BaseModel `bson:",inline"`
+	Value     string `bson:"value,omitempty"`
+}
+
+func TestCreateTTLIndex(t *testing.T) {
+	if testing.Short() {
+		t.Skip("TTL monitor runs every 60 seconds")
+	}
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	token := new(Token)
+	token.Value = "expired"
+	op := NewDo(s, dbName, token)
+	if err := op.CreateTTLIndex("CreatedAt", time.Second); err != nil {
+		t.Fatalf("Err during create ttl index: %v", err)
+	}
+	if err := op.Create(); err != nil {
+		t.Fatalf("Err during create: %v", err)
+	}
+
+	for i := 0; i < 13; i++ {
+		time.Sleep(10 * time.Second)
+		if err := NewDo(s, dbName, &Token{BaseModel: BaseModel{Id: token.Id}}).Get(); errors.Is(err, mgo.ErrNotFound) {
+			return
+		}
+	}
+	t.Errorf("Record should be removed after expired")
+}
+
+type Place struct {
+	BaseModel `bson:",inline"`
+	Name      string `bson:"name,omitempty"`
+	Location  bson.M `bson:"location,omitempty"`
+}
+
+func TestNear(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	op := NewDo(s, dbName, new(Place))
+	op.EraseAll()
+	if err := op.EnsureIndex(mgo.Index{Key: []string{"$2dsphere:location"}}); err != nil {
+		t.Fatalf("Err during ensure index: %v", err)
+	}
+	places := map[string][]float64{
+		"Bund":     {121.4903, 31.2397},
+		"Pudong":   {121.5447, 31.2215},
+		"Hongqiao": {121.3364, 31.1979},
+	}
+	for name, p := range places {
+		place := &Place{Name: name, Location: bson.M{"type": "Point", "coordinates": p}}
+		if err := NewDo(s, dbName, place).Create(); err != nil {
+			t.Fatalf("Err during create: %v", err)
+		}
+	}
+
+	var near []Place
+	if err := NewDo(s, dbName, new(Place)).Near("location", 121.4903, 31.2397, 6000, &near); err != nil {
+		t.Fatalf("Err during near: %v", err)
+	}
+	if len(near) != 2 || near[0].Name != "Bund" {
+		t.Errorf("Bund and Pudong expected, got %v", near)
+	}
+
+	var within []Place
+	if err := NewDo(s, dbName, new(Place)).GeoWithin("location", 121.4903, 31.2397, 1000, &within); err != nil {
+		t.Fatalf("Err during geo within: %v", err)
+	}
+	if len(within) != 1 {
+		t.Errorf("Only Bund expected, got %v", within)
+	}
+}
+
+func TestCreateIfNotExists(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	user := new(User)
+	user.Name = "Once"
+	op := NewDo(s, dbName, user)
+	if err := op.CreateIfNotExists(); err != nil {
+		t.Fatalf("Err during create: %v", err)
+	}
+	err = op.CreateIfNotExists()
+	var doErr *DoError
+	if !errors.As(err, &doErr) || !mgo.IsDup(doErr.Err) {
+		t.Fatalf("Duplicate error expected, got %v", err)
+	}
+	if doErr.Operation != "CreateIfNotExists" || doErr.ModelName != "User" || doErr.DocumentId != user.Id {
+		t.Errorf("Error context not set, got %+v", doErr)
+	}
+}
+
+func TestPool(t *testing.T) {
+	pool, err := NewPool(dial, 5*time.Second, 3, time.Minute)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+	defer pool.Close()
+
+	var mu sync.Mutex
+	maxInUse := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			op, err := NewDoFromPool(pool, dbName, new(User))
+			if err != nil {
+				t.Errorf("Err during acquire: %v", err)
+				return
+			}
+			defer op.Close()
+
+			mu.Lock()
+			if n := pool.InUse(); n > maxInUse {
+				maxInUse = n
+			}
+			mu.Unlock()
+			op.Count()
+		}()
+	}
+	wg.Wait()
+	if maxInUse > 3 {
+		t.Errorf("At most 3 sessions in use expected, got %d", maxInUse)
+	}
+	if pool.InUse() != 0 {
+		t.Errorf("All sessions should be released, %d in use", pool.InUse())
+	}
+	// options of a pooled Do do not leak to the next Acquire
+	op, err := NewDoFromPool(pool, dbName, new(User))
+	if err != nil {
+		t.Fatalf("Err during acquire: %v", err)
+	}
+	op.WithReadPreference(mgo.Nearest).Close()
+	s, err := pool.Acquire()
+	if err != nil {
+		t.Fatalf("Err during acquire: %v", err)
+	}
+	defer pool.Release(s)
+	if s.Mode() != pool.root.Mode() {
+		t.Errorf("Released session should have mode %v, got %v", pool.root.Mode(), s.Mode())
+	}
+}
+
+func TestBaseModel(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	user := new(User)
+	user.Name = "Base"
+	op := NewDo(s, dbName, user)
+	op.Operator = "tester"
+	if err := op.Create(); err != nil {
+		t.Fatalf("Err during create: %v", err)
+	}
+	if !user.Id.Valid() || user.CreatedAt.IsZero() || user.CreatedBy != "tester" {
+		t.Errorf("Id, CreatedAt and CreatedBy should be set, got %+v", user.BaseModel)
+	}
+
+	user.Age = 30
+	if err := op.Save(); err != nil {
+		t.Fatalf("Err during save: %v", err)
+	}
+	if user.UpdatedAt.IsZero() || user.UpdatedBy != "tester" {
+		t.Errorf("UpdatedAt and UpdatedBy should be set, got %+v", user.BaseModel)
+	}
+
+	if err := op.Delete(); err != nil {
+		t.Fatalf("Err during delete: %v", err)
+	}
+	if !user.IsRemoved || user.RemovedAt.IsZero() || user.RemovedBy != "tester" {
+		t.Errorf("IsRemoved, RemovedAt and RemovedBy should be set, got %+v", user.BaseModel)
+	}
+	if err := NewDo(s, dbName, &User{BaseModel: BaseModel{Id: user.Id}}).Get(); !errors.Is(err, mgo.ErrNotFound) {
+		t.Errorf("Removed record should not be found, got %v", err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	user := new(User)
+	user.Name = "Slow"
+	NewDo(s, dbName, user).Create()
+
+	op := NewDo(s, dbName, new(User)).WithTimeout(100 * time.Millisecond)
+	defer op.Close()
+	// server side sleep to simulate a slow server
+	op.Query = bson.M{"$where": "sleep(1000) || true"}
+	start := time.Now()
+	var users []*User
+	if err := op.FindAll(&users); err == nil {
+		t.Errorf("Timeout error expected")
+	}
+	if d := time.Since(start); d > 900*time.Millisecond {
+		t.Errorf("Should fail after timeout, took %v", d)
+	}
+}
+
+func TestDropCollection(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	user := new(User)
+	user.Name = "Dropped"
+	op := NewDo(s, dbName, user)
+	if err := op.Create(); err != nil {
+		t.Fatalf("Err during create: %v", err)
+	}
+	if err := op.DropCollection(false); err != nil {
+		t.Fatalf("Err during drop: %v", err)
+	}
+	names, err := s.DB(dbName).CollectionNames()
+	if err != nil {
+		t.Fatalf("Err during list collections: %v", err)
+	}
+	for _, name := range names {
+		if name == "User" {
+			t.Errorf("Collection User should be dropped")
+		}
+	}
+
+	logOp := NewDoWithCollectionName(s, dbName, "UserChangeLog", user, "", "")
+	if err := logOp.DropCollection(false); err == nil {
+		t.Errorf("Drop of changelog collection should be refused")
+	}
+}
+
+func TestSoftDeletedCount(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	name := bson.NewObjectId().Hex()
+	user := &User{Name: name}
+	if err := NewDo(s, dbName, user).Create(); err != nil {
+		t.Fatalf("Err during create: %v", err)
+	}
+	if err := NewDo(s, dbName, user).Delete(); err != nil {
+		t.Fatalf("Err during delete: %v", err)
+	}
+	if err := NewDo(s, dbName, &User{Name: name}).Create(); err != nil {
+		t.Fatalf("Err during create: %v", err)
+	}
+
+	// Count and SoftDeletedCount on the same Do, as a trash dashboard does
+	op := NewDo(s, dbName, new(User))
+	op.Query = bson.M{"name": name}
+	if n := op.Count(); n != 1 {
+		t.Errorf("Count expect 1, got %d", n)
+	}
+	if n := op.SoftDeletedCount(); n != 1 {
+		t.Errorf("SoftDeletedCount after Count expect 1, got %d", n)
+	}
+	if n := op.CountAll(); n != 2 {
+		t.Errorf("CountAll after Count expect 2, got %d", n)
+	}
+}
+
+type Draft struct {
+	BaseModel `bson:",inline"`
+	Title     string `bson:"title,omitempty"`
+	Note      string `bson:"-"`
+}
+
+func TestRollbackDelete(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	draft := &Draft{Title: "first"}
+	op := NewDo(s, dbName, draft)
+	if err := op.Create(); err != nil {
+		t.Fatalf("Err during create: %v", err)
+	}
+	if err := op.Checkpoint(); err != nil {
+		t.Fatalf("Err during checkpoint: %v", err)
+	}
+	if err := op.Delete(); err != nil {
+		t.Fatalf("Err during delete: %v", err)
+	}
+	draft.Note = "not stored"
+	if err := op.Rollback(); err != nil {
+		t.Fatalf("Err during rollback: %v", err)
+	}
+	if draft.IsRemoved || draft.Title != "first" || draft.Note != "not stored" {
+		t.Errorf("Model should be restored except fields not stored, got %+v", draft)
+	}
+
+	got := &Draft{BaseModel: BaseModel{Id: draft.Id}}
+	if err := NewDo(s, dbName, got).Get(); err != nil {
+		t.Fatalf("Rolled back record should not be soft deleted, got %v", err)
+	}
+	if got.IsRemoved || got.Title != "first" {
+		t.Errorf("Stored record should be restored, got %+v", got)
+	}
+}
+
+func TestRefreshBeforeRead(t *testing.T) {
+	s, err := mgo.Dial(dial)
+	if err != nil {
+		panic("Cannot connect to database")
+	}
+
+	user := &User{Name: "Tom"}
+	if err := NewDo(s, dbName, user).Create(); err != nil {
+		t.Fatalf("Err during create: %v", err)
+	}
+	p := cache.NewMemory()
+	NewDo(s, dbName, &User{BaseModel: BaseModel{Id: user.Id}}).WithCache(p, time.Minute).Get()
+
+	op := NewDo(s, dbName, &User{BaseModel: BaseModel{Id: user.Id}}).WithCache(p, time.Minute)
+	op.RefreshBeforeRead = true
+	defer op.Close()
+	var users []User
+	if err := op.FindByIds([]bson.ObjectId{user.Id}, &users); err != nil || len(users) != 1 {
+		t.Fatalf("Err during find by ids: %v", err)
+	}
+	if op.session == s || !op.ownSession {
+		t.Errorf("Refresh should be done on a private copy of session")
+	}
+	if op.fromCache() {
+		t.Errorf("Get should not read from cache with RefreshBeforeRead")
+	}
+}
+
+func TestIsLogCollection(t *testing.T) {
+	logs := &mgo.Collection{Name: "AuditTrail"}
+	for name, want := range map[string]bool{"User": false, "UserChangeLog": true, "UserChangeLogs": true, "AuditTrail": true} {
+		op := &Do{model: new(User), collection: &mgo.Collection{Name: name}, logCollection: logs}
+		if got := op.isLogCollection(); got != want {
+			t.Errorf("Collection %s as changelog expect %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestPublishId(t *testing.T) {
+	var got []interface{}
+	Events.Subscribe("TestPublishId", func(modelName string, id interface{}) {
+		got = append(got, id)
+	})
+	user := new(User)
+	user.Id = bson.NewObjectId()
+	(&Do{model: user}).publish("TestPublishId")
+	(&Do{model: &struct{ Id string }{Id: "slug"}}).publish("TestPublishId")
+	(&Do{model: &struct{ Id int }{Id: 7}}).publish("TestPublishId")
+	want := []interface{}{user.Id, "slug", 7}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Published ids expect %v, got %v", want, got)
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	p := cache.NewMemory()
+	coll := &mgo.Collection{FullName: dbName + ".User"}
+	user := new(User)
+	user.Id = bson.NewObjectId()
+	user.Name = "Tom"
+	op := (&Do{model: user, collection: coll}).WithCache(p, time.Minute)
+	op.toCache()
+
+	got := &User{}
+	got.Id = user.Id
+	if !(&Do{model: got, collection: coll}).WithCache(p, time.Minute).fromCache() || got.Name != "Tom" {
+		t.Errorf("Record should be read from cache, got %+v", got)
+	}
+	if (&Do{model: got, collection: coll}).WithCache(p, time.Minute).Tenant("t1").fromCache() {
+		t.Errorf("Cache of other tenant should not be read")
+	}
+
+	place := &Place{Location: bson.M{"type": "Point"}}
+	place.Id = bson.NewObjectId()
+	placeOp := (&Do{model: place, collection: coll}).WithCache(p, time.Minute)
+	placeOp.toCache()
+	place.Location["type"] = "changed after cached"
+	filled := &Place{BaseModel: BaseModel{Id: place.Id}}
+	(&Do{model: filled, collection: coll}).WithCache(p, time.Minute).fromCache()
+	filled.Location["type"] = "changed after read"
+	again := &Place{BaseModel: BaseModel{Id: place.Id}}
+	(&Do{model: again, collection: coll}).WithCache(p, time.Minute).fromCache()
+	if again.Location["type"] != "Point" {
+		t.Errorf("Cached record should not share maps with models, got %v", again.Location)
+	}
+
+	op.uncache()
+	if op.fromCache() {
+		t.Errorf("Record should be removed from cache by uncache")
+	}
+
+	op.toCache()
+	op.uncacheIds([]interface{}{bson.NewObjectId(), user.Id})
+	if op.fromCache() {
+		t.Errorf("Record should be removed from cache by uncacheIds")
+	}
+}
+
+type Invalid struct {
+	BaseModel `bson:",inline"`
+	seen      BaseModel
+}
+
+func (i *Invalid) Validate() error {
+	i.seen = i.BaseModel
+	return errors.New("invalid")
+}
+
+func TestValidateBeforeStamp(t *testing.T) {
+	model := new(Invalid)
+	if err := (&Do{model: model}).Create(); err == nil {
+		t.Fatalf("Create of invalid model should fail")
+	}
+	if model.seen.Id != "" || !model.seen.CreatedAt.IsZero() {
+		t.Errorf("Validate should see the model before stamped, got %+v", model.seen)
+	}
+	if model.Id != "" || !model.CreatedAt.IsZero() {
+		t.Errorf("Model should not be stamped when not written, got %+v", model.BaseModel)
+	}
+}
+
+type Slug struct {
+	Id        string    `bson:"_id"`
+	CreatedAt time.Time `bson:"CreatedAt"`
+	CreatedBy string    `bson:"CreatedBy"`
+}
+
+type opLogger struct {
+	operations []string
+}
+
+func (l *opLogger) Log(operation, collection string, duration time.Duration, err error) {
+	l.operations = append(l.operations, operation)
+}
+
+func TestTraceOutermost(t *testing.T) {
+	l := &opLogger{}
+	SetLogger(l)
+	defer SetLogger(nil)
+
+	op := &Do{model: new(Invalid)}
+	err := op.CreateWithLog()
+	var doErr *DoError
+	if !errors.As(err, &doErr) || doErr.Operation != "CreateWithLog" {
+		t.Errorf("Error should be wrapped with CreateWithLog, got %v", err)
+	}
+	if !reflect.DeepEqual(l.operations, []string{"CreateWithLog"}) {
+		t.Errorf("Only the outermost operation should be traced, got %v", l.operations)
+	}
+	if op.depth != 0 {
+		t.Errorf("Depth should be 0 after operation, got %d", op.depth)
+	}
+}
+
+func TestStampNewCustomId(t *testing.T) {
+	slug := new(Slug)
+	if err := (&Do{model: slug}).Create(); err == nil {
+		t.Errorf("Create of zero string Id without CustomId should fail")
+	}
+	if !slug.CreatedAt.IsZero() {
+		t.Errorf("Model should not be stamped when Id is missing")
+	}
+	if err := (&Do{model: slug}).CustomId(7).stampNew(); err == nil {
+		t.Errorf("CustomId of other type than Id should fail")
+	}
+	if err := (&Do{model: slug}).CustomId("a").stampNew(); err != nil || slug.Id != "a" || slug.CreatedAt.IsZero() {
+		t.Errorf("CustomId should be set as Id, got %+v %v", slug, err)
+	}
+	if err := (&Do{model: &Slug{Id: "b"}}).stampNew(); err != nil {
+		t.Errorf("Assigned string Id should be kept, got %v", err)
+	}
+}
+
+func TestTenantQ(t *testing.T) {
+	op := (&Do{model: new(User)}).Tenant("t1")
+
+	// $and of caller is kept as is, whatever its type
+	query := bson.M{"$and": []bson.M{{"name": "a"}, {"age": 1}}}
+	want := bson.M{"$and": []interface{}{query, bson.M{TenantField: "t1"}}}
+	if q := op.tenantQ(query); !reflect.DeepEqual(q, want) {
+		t.Errorf("Tenant query expect %v, got %v", want, q)
+	}
+	if q := op.tenantQ(bson.M{}); !reflect.DeepEqual(q, bson.M{TenantField: "t1"}) {
+		t.Errorf("Tenant query of empty query expect only tenant, got %v", q)
+	}
+	and := op.notRemoved(bson.M{"name": "a"})["$and"].([]interface{})
+	if !reflect.DeepEqual(and[len(and)-1], bson.M{TenantField: "t1"}) {
+		t.Errorf("Tenant should be in guard of removed, got %v", and)
+	}
+	if cl := op.newLog(&User{}, UPDATE); cl.TenantId != "t1" {
+		t.Errorf("Tenant should be stamped on change log, got %q", cl.TenantId)
+	}
+
+	other := &Do{model: new(User)}
+	if q := other.tenantQ(query); !reflect.DeepEqual(q, query) {
+		t.Errorf("Query without tenant should not change, got %v", q)
+	}
+}
+
+func TestAndOfCaller(t *testing.T) {
+	// $and of caller as []bson.M is kept by Where, OrQ and notRemoved
+	op := &Do{model: new(User), Query: bson.M{"$and": []bson.M{{"name": "a"}}}}
+	op.Where(bson.M{"age": 1}).OrQ([]bson.M{{"x": 1}}).OrQ([]bson.M{{"y": 1}})
+	want := []interface{}{bson.M{"name": "a"}, bson.M{"age": 1}, bson.M{"$or": []bson.M{{"x": 1}}}}
+	if and := op.Query["$and"]; !reflect.DeepEqual(and, want) {
+		t.Errorf("$and expect %v, got %v", want, and)
+	}
+	and := op.notRemoved(op.Query)["$and"].([]interface{})
+	if !reflect.DeepEqual(and[:len(want)], want) {
+		t.Errorf("notRemoved should keep $and of query, got %v", and)
+	}
+	if len(op.Query["$and"].([]interface{})) != len(want) {
+		t.Errorf("notRemoved should not modify Query")
+	}
+}
+
+func TestLazyNewDo(t *testing.T) {
+	down := errors.New("server down")
+	connect := func() (*mgo.Session, error) { return nil, down }
+	op := LazyNewDo(connect, dbName, new(User)).WithTimeout(time.Second).WithReadPreference(mgo.Nearest)
+	defer op.Close()
+
+	if len(op.lazy.options) != 2 {
+		t.Errorf("Options should be kept until connected, got %d", len(op.lazy.options))
+	}
+	if err := op.Get(); !errors.Is(err, down) {
+		t.Errorf("Get expect %v, got %v", down, err)
+	}
+	if op.Clone().WithTimeout(time.Second); len(op.lazy.options) != 2 {
+		t.Errorf("Options of clone should not change original, got %d", len(op.lazy.options))
+	}
+}
@@ -0,0 +1,122 @@
+// Package mgodotest provide a mock of mgodo.Doer for unit tests without MongoDB
+package mgodotest
+
+import (
+	"reflect"
+	"sync"
+
+	"mgodo"
+)
+
+//Call is one recorded call of MockSession
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+//MockSession implement mgodo.Doer, record calls and return configured responses
+//Errors[method] is returned by method. For Get and GetByQ, Results[method] is
+//copied into Model; for FindAll it is copied into the given slice pointer.
+type MockSession struct {
+	Model      interface{}
+	Errors     map[string]error
+	Results    map[string]interface{}
+	CountValue int64
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ mgodo.Doer = (*MockSession)(nil)
+
+//NewMockSession create a MockSession for model, a pointer as passed to mgodo.NewDo
+func NewMockSession(model interface{}) *MockSession {
+	return &MockSession{
+		Model:   model,
+		Errors:  map[string]error{},
+		Results: map[string]interface{}{},
+	}
+}
+
+//Calls return recorded calls in order
+func (s *MockSession) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call{}, s.calls...)
+}
+
+//Called count calls of method
+func (s *MockSession) Called(method string) int {
+	n := 0
+	for _, c := range s.Calls() {
+		if c.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+//record save call and return configured error of method
+func (s *MockSession) record(method string, args ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, Call{Method: method, Args: args})
+	return s.Errors[method]
+}
+
+//fill copy configured result of method into target pointer
+func (s *MockSession) fill(method string, target interface{}) {
+	v, found := s.Results[method]
+	if !found || target == nil {
+		return
+	}
+	dst := reflect.ValueOf(target)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return
+	}
+	src := reflect.ValueOf(v)
+	if src.Kind() == reflect.Ptr && src.Type() == dst.Type() {
+		src = src.Elem()
+	}
+	if src.Type().AssignableTo(dst.Elem().Type()) {
+		dst.Elem().Set(src)
+	}
+}
+
+func (s *MockSession) Create() error        { return s.record("Create") }
+func (s *MockSession) CreateWithLog() error { return s.record("CreateWithLog") }
+func (s *MockSession) Save() error          { return s.record("Save") }
+func (s *MockSession) SaveWithLog() error   { return s.record("SaveWithLog") }
+func (s *MockSession) Delete() error        { return s.record("Delete") }
+func (s *MockSession) DeleteWithLog() error { return s.record("DeleteWithLog") }
+func (s *MockSession) Erase() error         { return s.record("Erase") }
+func (s *MockSession) EraseWithLog() error  { return s.record("EraseWithLog") }
+
+func (s *MockSession) Get() error {
+	err := s.record("Get")
+	if err == nil {
+		s.fill("Get", s.Model)
+	}
+	return err
+}
+
+func (s *MockSession) GetByQ() error {
+	err := s.record("GetByQ")
+	if err == nil {
+		s.fill("GetByQ", s.Model)
+	}
+	return err
+}
+
+func (s *MockSession) FindAll(i interface{}) error {
+	err := s.record("FindAll", i)
+	if err == nil {
+		s.fill("FindAll", i)
+	}
+	return err
+}
+
+func (s *MockSession) Count() int64 {
+	s.record("Count")
+	return s.CountValue
+}
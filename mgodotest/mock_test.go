@@ -0,0 +1,71 @@
+package mgodotest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type item struct {
+	Name string
+}
+
+func TestMockFill(t *testing.T) {
+	model := new(item)
+	s := NewMockSession(model)
+
+	s.Results["Get"] = &item{Name: "pointer"}
+	if err := s.Get(); err != nil || model.Name != "pointer" {
+		t.Errorf("Get should fill model from pointer, got %+v %v", model, err)
+	}
+	s.Results["GetByQ"] = item{Name: "value"}
+	if err := s.GetByQ(); err != nil || model.Name != "value" {
+		t.Errorf("GetByQ should fill model from value, got %+v %v", model, err)
+	}
+
+	want := []item{{Name: "a"}, {Name: "b"}}
+	s.Results["FindAll"] = want
+	var all []item
+	if err := s.FindAll(&all); err != nil || !reflect.DeepEqual(all, want) {
+		t.Errorf("FindAll should fill slice, got %+v %v", all, err)
+	}
+
+	var wrongType []string
+	if err := s.FindAll(&wrongType); err != nil || wrongType != nil {
+		t.Errorf("FindAll should not fill slice of other type, got %v %v", wrongType, err)
+	}
+}
+
+func TestMockErrorsAndCalls(t *testing.T) {
+	model := new(item)
+	s := NewMockSession(model)
+	failed := errors.New("failed")
+	s.Errors["Get"] = failed
+	s.Results["Get"] = item{Name: "not filled"}
+	s.CountValue = 3
+
+	if err := s.Get(); err != failed {
+		t.Errorf("Get should return configured error, got %v", err)
+	}
+	if model.Name != "" {
+		t.Errorf("Model should not be filled on error, got %+v", model)
+	}
+	if err := s.Save(); err != nil {
+		t.Errorf("Save without configured error should succeed, got %v", err)
+	}
+	if n := s.Count(); n != 3 {
+		t.Errorf("Count should return CountValue, got %d", n)
+	}
+	s.Get()
+
+	var methods []string
+	for _, c := range s.Calls() {
+		methods = append(methods, c.Method)
+	}
+	if want := []string{"Get", "Save", "Count", "Get"}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("Calls expect %v, got %v", want, methods)
+	}
+	if s.Called("Get") != 2 || s.Called("Save") != 1 || s.Called("Erase") != 0 {
+		t.Errorf("Called counts wrong, got Get %d Save %d Erase %d", s.Called("Get"), s.Called("Save"), s.Called("Erase"))
+	}
+}
@@ -0,0 +1,92 @@
+package mgodo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/globalsign/mgo"
+)
+
+//MigrationCollection keeps applied migrations
+const MigrationCollection = "Migrations"
+
+//migration registered to Migrate
+type migration struct {
+	name string
+	up   func(*mgo.Session) error
+	down func(*mgo.Session) error
+}
+
+//MigrationRecord is document of applied migration
+type MigrationRecord struct {
+	Name      string    `bson:"_id"`
+	AppliedAt time.Time `bson:"AppliedAt"`
+}
+
+//Migrate run registered migrations once, in registration order
+type Migrate struct {
+	migrations []migration
+}
+
+//Register add a migration, name should be unique and never changed once applied
+func (g *Migrate) Register(name string, up func(*mgo.Session) error, down func(*mgo.Session) error) {
+	g.migrations = append(g.migrations, migration{name: name, up: up, down: down})
+}
+
+//applied return names of applied migrations
+func (g *Migrate) applied(c *mgo.Collection) (map[string]bool, error) {
+	var records []MigrationRecord
+	err := c.Find(nil).All(&records)
+	done := map[string]bool{}
+	for _, r := range records {
+		done[r.Name] = true
+	}
+	return done, err
+}
+
+//Run apply migrations not applied yet, stop at first error
+func (g *Migrate) Run(session *mgo.Session, dbName string) error {
+	c := session.DB(dbName).C(MigrationCollection)
+	done, err := g.applied(c)
+	if err != nil {
+		return err
+	}
+	for _, mg := range g.migrations {
+		if done[mg.name] {
+			continue
+		}
+		if err := mg.up(session); err != nil {
+			return fmt.Errorf("migration %s failed: %s", mg.name, err)
+		}
+		err := c.Insert(MigrationRecord{Name: mg.name, AppliedAt: time.Now()})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Down revert applied migrations in reverse order, stop at first error
+func (g *Migrate) Down(session *mgo.Session, dbName string) error {
+	c := session.DB(dbName).C(MigrationCollection)
+	done, err := g.applied(c)
+	if err != nil {
+		return err
+	}
+	for i := len(g.migrations) - 1; i >= 0; i-- {
+		mg := g.migrations[i]
+		if !done[mg.name] {
+			continue
+		}
+		if mg.down != nil {
+			if err := mg.down(session); err != nil {
+				return fmt.Errorf("migration %s down failed: %s", mg.name, err)
+			}
+		}
+		err := c.RemoveId(mg.name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package mgodo
+
+//PageResult hold one page of FindPage
+type PageResult struct {
+	Total      int64
+	PageNum    int
+	PageSize   int
+	TotalPages int
+	Items      interface{} // the slice passed to FindPage
+}
+
+//Page set Skip and Limit for pageNum (start from 1) of pageSize records
+func (m *Do) Page(pageNum, pageSize int) *Do {
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	m.Skip = (pageNum - 1) * pageSize
+	m.Limit = pageSize
+	return m
+}
+
+//FindPage find records of current page to i, and count total records of query
+func (m *Do) FindPage(i interface{}) (_ PageResult, err error) {
+	defer m.trace("FindPage", m.enter(), &err)
+	result := PageResult{PageNum: 1, PageSize: m.Limit, Items: i}
+	if err := m.ctxErr(); err != nil {
+		return result, err
+	}
+	if m.Limit > 0 {
+		result.PageNum = m.Skip/m.Limit + 1
+	}
+
+	// count without skip and limit
+	total, err := m.find(m.notRemovedQ()).Count()
+	if err != nil {
+		return result, err
+	}
+	result.Total = int64(total)
+	if m.Limit > 0 {
+		result.TotalPages = (total + m.Limit - 1) / m.Limit
+	} else if total > 0 {
+		result.TotalPages = 1
+	}
+
+	err = m.findQ().All(i)
+	return result, err
+}
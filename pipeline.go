@@ -0,0 +1,72 @@
+package mgodo
+
+import (
+	"github.com/globalsign/mgo/bson"
+)
+
+//Pipeline build stages of aggregation by chain, run it by Exec
+//e.g. NewPipeline().Match(bson.M{"age": bson.M{"$gt": 18}}).Group(bson.M{"_id": "$city"})
+type Pipeline struct {
+	stages []bson.M
+}
+
+//NewPipeline create an empty Pipeline
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+func (p *Pipeline) add(stage string, value interface{}) *Pipeline {
+	p.stages = append(p.stages, bson.M{stage: value})
+	return p
+}
+
+//Match add $match stage
+func (p *Pipeline) Match(query bson.M) *Pipeline {
+	return p.add("$match", query)
+}
+
+//Group add $group stage
+func (p *Pipeline) Group(group bson.M) *Pipeline {
+	return p.add("$group", group)
+}
+
+//Project add $project stage
+func (p *Pipeline) Project(project bson.M) *Pipeline {
+	return p.add("$project", project)
+}
+
+//Sort add $sort stage, order of keys in bson.M is random, one key per Sort for sure
+func (p *Pipeline) Sort(sort bson.M) *Pipeline {
+	return p.add("$sort", sort)
+}
+
+//Limit add $limit stage
+func (p *Pipeline) Limit(n int) *Pipeline {
+	return p.add("$limit", n)
+}
+
+//Skip add $skip stage
+func (p *Pipeline) Skip(n int) *Pipeline {
+	return p.add("$skip", n)
+}
+
+//Lookup add $lookup stage, join records of collection from into field as
+func (p *Pipeline) Lookup(from, localField, foreignField, as string) *Pipeline {
+	return p.add("$lookup", bson.M{"from": from, "localField": localField, "foreignField": foreignField, "as": as})
+}
+
+//Unwind add $unwind stage of field, without "$" prefix
+func (p *Pipeline) Unwind(field string) *Pipeline {
+	return p.add("$unwind", "$"+field)
+}
+
+//Stages return stages added so far
+func (p *Pipeline) Stages() []bson.M {
+	return p.stages
+}
+
+//Exec run pipeline on do and put all results to result
+//$match of do.Query with IsRemoved: true excluded is prepended, see AggregateQ.
+func (p *Pipeline) Exec(do *Do, result interface{}) error {
+	return do.Aggregate(p.stages, result)
+}
@@ -0,0 +1,108 @@
+package mgodo
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo"
+)
+
+//ErrPoolClosed is returned by Acquire after Pool is closed
+var ErrPoolClosed = errors.New("Session pool is closed.")
+
+//Pool manage copies of one dialed session, at most MaxSize are in use at the same time
+type Pool struct {
+	root        *mgo.Session
+	sem         chan struct{}
+	idleTimeout time.Duration
+
+	mu     sync.Mutex
+	idle   []idleSession
+	closed bool
+}
+
+type idleSession struct {
+	session *mgo.Session
+	since   time.Time
+}
+
+//NewPool dial url with timeout, maxSize limits sessions in use, idle sessions are closed after idleTimeout
+func NewPool(url string, timeout time.Duration, maxSize int, idleTimeout time.Duration) (*Pool, error) {
+	if maxSize < 1 {
+		return nil, errors.New("Pool size should be at least 1.")
+	}
+	s, err := mgo.DialWithTimeout(url, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &Pool{root: s, sem: make(chan struct{}, maxSize), idleTimeout: idleTimeout}, nil
+}
+
+//Acquire get a session, block until one is released if MaxSize is reached
+func (p *Pool) Acquire() (*mgo.Session, error) {
+	p.sem <- struct{}{}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		<-p.sem
+		return nil, ErrPoolClosed
+	}
+	for len(p.idle) > 0 {
+		last := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if p.idleTimeout > 0 && time.Since(last.since) > p.idleTimeout {
+			last.session.Close()
+			continue
+		}
+		return last.session, nil
+	}
+	return p.root.Copy(), nil
+}
+
+//Release return session from Acquire to pool
+//Its sockets are released, mode and safety are reset to the dialed session.
+func (p *Pool) Release(s *mgo.Session) {
+	p.mu.Lock()
+	if p.closed {
+		s.Close()
+	} else {
+		s.SetMode(p.root.Mode(), true)
+		s.SetSafe(p.root.Safe())
+		p.idle = append(p.idle, idleSession{session: s, since: time.Now()})
+	}
+	p.mu.Unlock()
+	<-p.sem
+}
+
+//InUse return number of acquired sessions not released yet
+func (p *Pool) InUse() int {
+	return len(p.sem)
+}
+
+//Close close idle sessions and the dialed session
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, i := range p.idle {
+		i.session.Close()
+	}
+	p.idle = nil
+	p.closed = true
+	p.root.Close()
+}
+
+//NewDoFromPool acquire a session from pool for Do, Close to release it
+//Options of session like WithTimeout are set on a copy, the pooled session
+//is returned unchanged.
+func NewDoFromPool(p *Pool, dbName string, model interface{}) (*Do, error) {
+	s, err := p.Acquire()
+	if err != nil {
+		return nil, err
+	}
+	do := NewDo(s, dbName, model)
+	do.pool = p
+	do.pooled = s
+	return do, nil
+}
@@ -0,0 +1,50 @@
+package mgodo
+
+import (
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+//WithRetry retry Create, Save, Delete, Erase, FindAll and Get on transient errors
+//Attempt n sleeps backoff*n before it, the session is copied so it can be
+//refreshed between attempts. Call Close to release the copied session.
+func (m *Do) WithRetry(maxAttempts int, backoff time.Duration) *Do {
+	m.copySession()
+	m.attempts = maxAttempts
+	m.backoff = backoff
+	return m
+}
+
+//retry call fn until it succeeds, fails with non-transient error or attempts run out
+func (m *Do) retry(fn func() error) error {
+	err := fn()
+	for attempt := 1; attempt < m.attempts && isTransient(err); attempt++ {
+		time.Sleep(m.backoff * time.Duration(attempt))
+		if ctxErr := m.ctxErr(); ctxErr != nil {
+			return ctxErr
+		}
+		m.session.Refresh()
+		err = fn()
+	}
+	return err
+}
+
+//isTransient check if err is a network error worth retrying
+//Server errors like duplicate key and mgo.ErrNotFound are not transient.
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "no reachable servers") ||
+		strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "broken pipe")
+}
@@ -0,0 +1,75 @@
+package mgodo
+
+import (
+	"github.com/globalsign/mgo/bson"
+)
+
+//textQ return query of text search merged with m.Query, skip IsRemoved:true
+func (m *Do) textQ(query string) bson.M {
+	q := m.notRemovedQ()
+	q["$text"] = bson.M{"$search": query}
+	return q
+}
+
+//TextSearch find records matching query by text index
+func (m *Do) TextSearch(query string, result interface{}) (err error) {
+	defer m.trace("TextSearch", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	err = m.findWithQ(m.textQ(query)).All(result)
+	return err
+}
+
+//TextSearchWithScore find records by text index, sorted by relevance
+//Text score is put to "score" field of result, Sort is applied after score.
+func (m *Do) TextSearchWithScore(query string, result interface{}) (err error) {
+	defer m.trace("TextSearchWithScore", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	sort := append([]string{"$textScore:score"}, m.Sort...)
+	err = m.findWithQ(m.textQ(query)).
+		Select(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		Sort(sort...).
+		All(result)
+	return err
+}
+
+//earthRadius in meters, to convert distance to radians
+const earthRadius = 6378100.0
+
+//geoPoint conduct GeoJSON point
+func geoPoint(lng, lat float64) bson.M {
+	return bson.M{"type": "Point", "coordinates": []float64{lng, lat}}
+}
+
+//Near find records by distance to point (lng, lat), nearest first, needs 2dsphere index on field
+func (m *Do) Near(field string, lng, lat, maxDistanceMeters float64, result interface{}) (err error) {
+	defer m.trace("Near", m.enter(), &err)
+	return m.geoFind(field, bson.M{"$near": bson.M{"$geometry": geoPoint(lng, lat), "$maxDistance": maxDistanceMeters}}, result)
+}
+
+//NearSphere is like Near, distance is calculated on sphere, needs 2dsphere index on field
+//The point is GeoJSON, a 2d index of legacy coordinate pairs is not used.
+func (m *Do) NearSphere(field string, lng, lat, maxDistanceMeters float64, result interface{}) (err error) {
+	defer m.trace("NearSphere", m.enter(), &err)
+	return m.geoFind(field, bson.M{"$nearSphere": bson.M{"$geometry": geoPoint(lng, lat), "$maxDistance": maxDistanceMeters}}, result)
+}
+
+//GeoWithin find records within radiusMeters of point (lng, lat), not sorted by distance
+func (m *Do) GeoWithin(field string, lng, lat, radiusMeters float64, result interface{}) (err error) {
+	defer m.trace("GeoWithin", m.enter(), &err)
+	return m.geoFind(field, bson.M{"$geoWithin": bson.M{"$centerSphere": []interface{}{[]float64{lng, lat}, radiusMeters / earthRadius}}}, result)
+}
+
+//geoFind find records with geo condition on field, merged with m.Query, skip IsRemoved:true
+func (m *Do) geoFind(field string, cond bson.M, result interface{}) error {
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	q := m.notRemovedQ()
+	q[field] = cond
+	err := m.findWithQ(q).All(result)
+	return err
+}
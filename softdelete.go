@@ -0,0 +1,115 @@
+package mgodo
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+//ErrNotSoftDeleted is returned by HardDeleteIfSoftDeleted if record is not soft deleted
+var ErrNotSoftDeleted = errors.New("Record is not soft deleted.")
+
+//SoftDeleteConfig name fields used by soft delete
+//Each name is used both as struct field of model (set by reflection) and as
+//key of record in queries, so the bson key of the field should be the same.
+type SoftDeleteConfig struct {
+	DeletedFlag string // bool, true when removed
+	DeletedAt   string // time.Time
+	DeletedBy   string // string, the operator
+}
+
+//DefaultSoftDeleteConfig match fields of BaseModel
+var DefaultSoftDeleteConfig = SoftDeleteConfig{
+	DeletedFlag: "IsRemoved",
+	DeletedAt:   "RemovedAt",
+	DeletedBy:   "RemovedBy",
+}
+
+//WithSoftDeleteConfig use fields of c for soft delete instead of IsRemoved, RemovedAt and RemovedBy
+func (m *Do) WithSoftDeleteConfig(c SoftDeleteConfig) *Do {
+	m.softDelete = &c
+	return m
+}
+
+//softDeleteConfig return config of Do, DefaultSoftDeleteConfig if not set
+func (m *Do) softDeleteConfig() SoftDeleteConfig {
+	if m.softDelete == nil {
+		return DefaultSoftDeleteConfig
+	}
+	return *m.softDelete
+}
+
+//removedQ is condition of records marked as removed
+func (m *Do) removedQ() bson.M {
+	if m.softDelete == nil {
+		return bson.M{"$or": []interface{}{bson.M{"is_removed": true}, bson.M{"IsRemoved": true}}}
+	}
+	return bson.M{m.softDeleteConfig().DeletedFlag: true}
+}
+
+//softDeleteAllQ is query of records for SoftDeleteAll, locked records are skipped
+func (m *Do) softDeleteAllQ() bson.M {
+	q := m.notRemovedQ()
+	q["$and"] = append(q["$and"].([]interface{}), bson.M{"IsLocked": bson.M{"$ne": true}})
+	return q
+}
+
+//SoftDeleteAll soft delete all records of Query in one update, locked records are skipped
+func (m *Do) SoftDeleteAll() (err error) {
+	defer m.trace("SoftDeleteAll", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	sd := m.softDeleteConfig()
+	update := bson.M{"$set": bson.M{sd.DeletedFlag: true, sd.DeletedAt: time.Now(), sd.DeletedBy: m.Operator}}
+	_, err = m.collection.UpdateAll(m.softDeleteAllQ(), update)
+	return err
+}
+
+//SoftDeleteAllWithLog soft delete all records of Query and insert one changelog per record
+func (m *Do) SoftDeleteAllWithLog() (err error) {
+	defer m.trace("SoftDeleteAllWithLog", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	// ids of any type, e.g. of CustomId
+	var records []bson.M
+	err = m.collection.Find(m.softDeleteAllQ()).Select(bson.M{"_id": 1}).All(&records)
+	if err != nil {
+		return err
+	}
+	ids := make([]interface{}, len(records))
+	for i, r := range records {
+		ids[i] = r["_id"]
+	}
+	return m.softDeleteIdsWithLog(ids)
+}
+
+//HardDeleteIfSoftDeleted erase record of model _id only if it is soft deleted, the purge of trash
+//errors.Is(err, ErrNotSoftDeleted) if record is not soft deleted or does not exist.
+func (m *Do) HardDeleteIfSoftDeleted() (err error) {
+	defer m.trace("HardDeleteIfSoftDeleted", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	id := reflect.ValueOf(m.model).Elem().FieldByName("Id").Interface()
+	m.uncache()
+	err = m.collection.Remove(m.tenantQ(bson.M{"$and": []interface{}{bson.M{"_id": id}, m.removedQ()}}))
+	if errors.Is(err, mgo.ErrNotFound) {
+		return ErrNotSoftDeleted
+	}
+	return err
+}
+
+//SoftDeleteByQ is SoftDeleteAll, records already marked as removed are not stamped again
+func (m *Do) SoftDeleteByQ() error {
+	return m.SoftDeleteAll()
+}
+
+//SoftDeleteByQWithLog is SoftDeleteAllWithLog
+func (m *Do) SoftDeleteByQWithLog() error {
+	return m.SoftDeleteAllWithLog()
+}
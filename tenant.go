@@ -0,0 +1,58 @@
+package mgodo
+
+import (
+	"github.com/globalsign/mgo/bson"
+)
+
+//TenantField is key of tenant id in records of Tenant
+const TenantField = "tenant_id"
+
+//Tenant scope all following queries and writes of Do to tenantId
+//{"tenant_id": tenantId} is ANDed to every query, and set in records written
+//by Create, Save, Delete, ... and in change logs. Records and change logs of
+//other tenants can be neither read nor overwritten. Watch is not scoped.
+func (m *Do) Tenant(tenantId string) *Do {
+	m.tenantId = tenantId
+	return m
+}
+
+//tenantQ AND tenant condition to query, query is nested as is, not modified
+func (m *Do) tenantQ(query bson.M) bson.M {
+	if m.tenantId == "" {
+		return query
+	}
+	if len(query) == 0 {
+		return bson.M{TenantField: m.tenantId}
+	}
+	return bson.M{"$and": []interface{}{query, bson.M{TenantField: m.tenantId}}}
+}
+
+//tenantDoc return model with tenant id to be written, model itself if no tenant
+func (m *Do) tenantDoc(model interface{}) interface{} {
+	if m.tenantId == "" {
+		return model
+	}
+	doc := bson.M{}
+	data, err := bson.Marshal(model)
+	if err != nil {
+		// let the write report the same error
+		return model
+	}
+	if err := bson.Unmarshal(data, doc); err != nil {
+		return model
+	}
+	doc[TenantField] = m.tenantId
+	return doc
+}
+
+//tenantDocs return tenantDoc of each doc
+func (m *Do) tenantDocs(docs []interface{}) []interface{} {
+	if m.tenantId == "" {
+		return docs
+	}
+	result := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		result[i] = m.tenantDoc(doc)
+	}
+	return result
+}
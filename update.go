@@ -0,0 +1,346 @@
+package mgodo
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+//locked check IsLocked flag of record
+func (m *Do) locked(id interface{}) bool {
+	record := map[string]interface{}{}
+	m.collection.Find(m.tenantQ(bson.M{"_id": id})).Select(bson.M{"IsLocked": 1}).One(&record)
+	if v, found := record["IsLocked"]; found {
+		if b, ok := v.(bool); ok && b {
+			return true
+		}
+	}
+	return false
+}
+
+//updateId apply update to record of model _id, with UpdatedAt and UpdatedBy set
+//model is reloaded with the updated record.
+func (m *Do) updateId(update bson.M) error {
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	id := reflect.ValueOf(m.model).Elem().FieldByName("Id").Interface()
+	if m.locked(id) {
+		return errors.New("Record is locked for update.")
+	}
+
+	set := bson.M{}
+	if v, found := update["$set"]; found {
+		for k, val := range v.(bson.M) {
+			set[k] = val
+		}
+	}
+	set["UpdatedAt"] = time.Now()
+	set["UpdatedBy"] = m.Operator
+	update["$set"] = set
+
+	m.uncache()
+	change := mgo.Change{Update: update, ReturnNew: true}
+	_, err := m.collection.Find(m.tenantQ(bson.M{"_id": id})).Apply(change, m.model)
+	return err
+}
+
+//Inc increase field by delta ($inc), negative delta to decrease
+func (m *Do) Inc(field string, delta int) (err error) {
+	defer m.trace("Inc", m.enter(), &err)
+	if field == "" {
+		return errors.New("Field name is empty.")
+	}
+	if delta == 0 {
+		return errors.New("Delta should not be 0.")
+	}
+	return m.updateId(bson.M{"$inc": bson.M{field: delta}})
+}
+
+//IncWithLog increase field and insert a changelog
+func (m *Do) IncWithLog(field string, delta int) (err error) {
+	defer m.trace("IncWithLog", m.enter(), &err)
+	err = m.Inc(field, delta)
+	if err != nil {
+		return err
+	}
+	err = m.saveLog(UPDATE)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//Push append value to array field ($push)
+func (m *Do) Push(field string, value interface{}) (err error) {
+	defer m.trace("Push", m.enter(), &err)
+	if field == "" {
+		return errors.New("Field name is empty.")
+	}
+	return m.updateId(bson.M{"$push": bson.M{field: value}})
+}
+
+//PushWithLog push value and insert a changelog
+func (m *Do) PushWithLog(field string, value interface{}) (err error) {
+	defer m.trace("PushWithLog", m.enter(), &err)
+	err = m.Push(field, value)
+	if err != nil {
+		return err
+	}
+	err = m.saveLog(UPDATE)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//Pull remove all matched value from array field ($pull)
+func (m *Do) Pull(field string, value interface{}) (err error) {
+	defer m.trace("Pull", m.enter(), &err)
+	if field == "" {
+		return errors.New("Field name is empty.")
+	}
+	return m.updateId(bson.M{"$pull": bson.M{field: value}})
+}
+
+//PullWithLog pull value and insert a changelog
+func (m *Do) PullWithLog(field string, value interface{}) (err error) {
+	defer m.trace("PullWithLog", m.enter(), &err)
+	err = m.Pull(field, value)
+	if err != nil {
+		return err
+	}
+	err = m.saveLog(UPDATE)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//AddToSet append value to array field if not exists ($addToSet)
+func (m *Do) AddToSet(field string, value interface{}) (err error) {
+	defer m.trace("AddToSet", m.enter(), &err)
+	if field == "" {
+		return errors.New("Field name is empty.")
+	}
+	return m.updateId(bson.M{"$addToSet": bson.M{field: value}})
+}
+
+//AddToSetWithLog add value to set and insert a changelog
+func (m *Do) AddToSetWithLog(field string, value interface{}) (err error) {
+	defer m.trace("AddToSetWithLog", m.enter(), &err)
+	err = m.AddToSet(field, value)
+	if err != nil {
+		return err
+	}
+	err = m.saveLog(UPDATE)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//UpsertByQ upsert record matching m.Query instead of _id, with UpdatedAt as now
+//Model is reloaded with the upserted record, so Id is set if it was inserted.
+func (m *Do) UpsertByQ() (err error) {
+	defer m.trace("UpsertByQ", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if len(m.Query) == 0 {
+		return errors.New("Query is empty.")
+	}
+	x := reflect.ValueOf(m.model).Elem().FieldByName("UpdatedAt")
+	x.Set(reflect.ValueOf(time.Now()))
+	by := reflect.ValueOf(m.model).Elem().FieldByName("UpdatedBy")
+	by.Set(reflect.ValueOf(m.Operator))
+
+	// check IsLocked flag
+	record := map[string]interface{}{}
+	m.collection.Find(m.tenantQ(m.Query)).Select(bson.M{"IsLocked": 1}).One(&record)
+	if v, found := record["IsLocked"]; found {
+		if b, ok := v.(bool); ok && b {
+			return errors.New("Record is locked for update.")
+		}
+	}
+
+	change := mgo.Change{Update: bson.M{"$set": m.tenantDoc(m.model)}, Upsert: true, ReturnNew: true}
+	_, err = m.collection.Find(m.tenantQ(m.Query)).Apply(change, m.model)
+	return err
+}
+
+//UpsertByQWithLog upsert record matching m.Query and insert a changelog
+func (m *Do) UpsertByQWithLog() (err error) {
+	defer m.trace("UpsertByQWithLog", m.enter(), &err)
+	err = m.UpsertByQ()
+	if err != nil {
+		return err
+	}
+	err = m.saveLog(UPDATE)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//SetFields update only given fields ($set), UpdatedAt and UpdatedBy are set automatically
+func (m *Do) SetFields(fields bson.M) (err error) {
+	defer m.trace("SetFields", m.enter(), &err)
+	if len(fields) == 0 {
+		return errors.New("Fields is empty.")
+	}
+	for _, k := range []string{"UpdatedAt", "UpdatedBy"} {
+		if _, found := fields[k]; found {
+			return errors.New(k + " is set automatically.")
+		}
+	}
+	return m.updateId(bson.M{"$set": fields})
+}
+
+//SetFieldsWithLog set fields and insert a changelog
+func (m *Do) SetFieldsWithLog(fields bson.M) (err error) {
+	defer m.trace("SetFieldsWithLog", m.enter(), &err)
+	err = m.SetFields(fields)
+	if err != nil {
+		return err
+	}
+	err = m.saveLog(UPDATE)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//UnsetFields remove given fields from record ($unset)
+func (m *Do) UnsetFields(fields []string) (err error) {
+	defer m.trace("UnsetFields", m.enter(), &err)
+	if len(fields) == 0 {
+		return errors.New("Fields is empty.")
+	}
+	unset := bson.M{}
+	for _, f := range fields {
+		if f == "UpdatedAt" || f == "UpdatedBy" {
+			return errors.New(f + " is set automatically.")
+		}
+		unset[f] = 1
+	}
+	return m.updateId(bson.M{"$unset": unset})
+}
+
+//UnsetFieldsWithLog unset fields and insert a changelog
+func (m *Do) UnsetFieldsWithLog(fields []string) (err error) {
+	defer m.trace("UnsetFieldsWithLog", m.enter(), &err)
+	err = m.UnsetFields(fields)
+	if err != nil {
+		return err
+	}
+	err = m.saveLog(UPDATE)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//ReplaceOne replace the whole record of model _id with model (no $set)
+//Fields not in model are removed. mgo.ErrNotFound if record does not exist.
+func (m *Do) ReplaceOne() (err error) {
+	defer m.trace("ReplaceOne", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return err
+	}
+	if err := m.validate(); err != nil {
+		return err
+	}
+	id := reflect.ValueOf(m.model).Elem().FieldByName("Id").Interface()
+	x := reflect.ValueOf(m.model).Elem().FieldByName("UpdatedAt")
+	x.Set(reflect.ValueOf(time.Now()))
+	by := reflect.ValueOf(m.model).Elem().FieldByName("UpdatedBy")
+	by.Set(reflect.ValueOf(m.Operator))
+
+	if m.locked(id) {
+		return errors.New("Record is locked for update.")
+	}
+	m.uncache()
+	err = m.collection.Update(m.tenantQ(bson.M{"_id": id}), m.tenantDoc(m.model))
+	return err
+}
+
+//GetOrCreate get first record of query to model, or create it from defaults if none matches
+//It is one atomic findAndModify upsert ($setOnInsert), created is true when
+//inserted. defaults is a model value or pointer, stamped like Create. Use a
+//unique index on the query fields to be safe from concurrent inserts.
+func (m *Do) GetOrCreate(defaults interface{}) (created bool, err error) {
+	defer m.trace("GetOrCreate", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return false, err
+	}
+	model := reflect.ValueOf(m.model).Elem()
+	dv := reflect.ValueOf(defaults)
+	if dv.Kind() == reflect.Ptr {
+		dv = dv.Elem()
+	}
+	if dv.Type() != model.Type() {
+		return false, errors.New("Defaults should be of the model type.")
+	}
+
+	doc := reflect.New(model.Type())
+	doc.Elem().Set(dv)
+	m.stampCreate(doc.Interface())
+	result := reflect.New(model.Type())
+	change := mgo.Change{Update: bson.M{"$setOnInsert": m.tenantDoc(doc.Interface())}, Upsert: true, ReturnNew: true}
+	info, err := m.findQ().Apply(change, result.Interface())
+	if err != nil {
+		return false, err
+	}
+	model.Set(result.Elem())
+	created = info != nil && info.UpsertedId != nil
+	if created {
+		m.publish(CREATE)
+	}
+	return created, nil
+}
+
+//UpdateWhere apply update to all records of query as is, return count of matched records
+//IsRemoved:true records are not skipped and UpdatedAt is not set, like EraseAll.
+func (m *Do) UpdateWhere(query bson.M, update bson.M) (_ int, err error) {
+	defer m.trace("UpdateWhere", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return 0, err
+	}
+	info, err := m.collection.UpdateAll(m.tenantQ(query), update)
+	if err != nil {
+		return 0, err
+	}
+	return info.Matched, nil
+}
+
+//UpdateWhereWithLog UpdateWhere and insert one changelog per updated record
+func (m *Do) UpdateWhereWithLog(query bson.M, update bson.M) (_ int, err error) {
+	defer m.trace("UpdateWhereWithLog", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return 0, err
+	}
+	var records []bson.M
+	err = m.collection.Find(m.tenantQ(query)).Select(bson.M{"_id": 1}).All(&records)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]interface{}, len(records))
+	for i, r := range records {
+		ids[i] = r["_id"]
+	}
+	// update exactly the logged records
+	info, err := m.collection.UpdateAll(bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return 0, err
+	}
+	records = nil
+	err = m.collection.Find(bson.M{"_id": bson.M{"$in": ids}}).All(&records)
+	if err != nil {
+		return info.Matched, err
+	}
+	return info.Matched, m.saveLogs(records, UPDATE)
+}
@@ -0,0 +1,96 @@
+package mgodo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+//ErrNotSupported is returned when MongoDB server does not support the feature, check by errors.Is
+var ErrNotSupported = errors.New("Not supported by MongoDB server.")
+
+//ChangeEvent is one change of collection from Watch
+type ChangeEvent struct {
+	OperationType string // insert, update, replace, delete, ...
+	FullDocument  bson.M
+	Timestamp     time.Time
+}
+
+//changeDoc is change stream document from MongoDB
+type changeDoc struct {
+	OperationType string              `bson:"operationType"`
+	FullDocument  bson.M              `bson:"fullDocument"`
+	ClusterTime   bson.MongoTimestamp `bson:"clusterTime"`
+}
+
+//Watch open change stream of collection (MongoDB 3.6+), pipeline filters the events
+//For update the current full document is looked up. Close stops the stream and
+//closes the channel.
+func (m *Do) Watch(pipeline []bson.M) (_ <-chan ChangeEvent, err error) {
+	defer m.trace("Watch", m.enter(), &err)
+	if err := m.ctxErr(); err != nil {
+		return nil, err
+	}
+	info, err := m.session.BuildInfo()
+	if err != nil {
+		return nil, err
+	}
+	if !info.VersionAtLeast(3, 6) {
+		return nil, ErrNotSupported
+	}
+	if pipeline == nil {
+		pipeline = []bson.M{}
+	}
+
+	stream, err := m.collection.Watch(pipeline, mgo.ChangeStreamOptions{
+		FullDocument:   mgo.UpdateLookup,
+		MaxAwaitTimeMS: time.Second, // to check stop periodically
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.stopWatch()
+	stop := make(chan struct{})
+	m.watchStop = stop
+	events := make(chan ChangeEvent)
+	go func() {
+		defer close(events)
+		defer stream.Close()
+		for {
+			var doc changeDoc
+			if stream.Next(&doc) {
+				event := ChangeEvent{
+					OperationType: doc.OperationType,
+					FullDocument:  doc.FullDocument,
+					Timestamp:     time.Unix(int64(doc.ClusterTime)>>32, 0),
+				}
+				select {
+				case events <- event:
+				case <-stop:
+					return
+				}
+				continue
+			}
+			if !stream.Timeout() {
+				return
+			}
+			select {
+			case <-stop:
+				return
+			default:
+			}
+		}
+	}()
+	return events, nil
+}
+
+//stopWatch stop change stream opened by Watch
+func (m *Do) stopWatch() {
+	if m.watchStop != nil {
+		close(m.watchStop)
+		m.watchStop = nil
+	}
+}